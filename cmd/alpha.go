@@ -22,7 +22,7 @@ import (
 
 // newAlphaCommand returns alpha subcommand which will be mounted
 // at the root command by the caller.
-func newAlphaCommand() *cobra.Command {
+func newAlphaCommand(layout layoutCommands) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "alpha",
 		Short: "Expose commands which are in experimental or early stages of development",
@@ -30,11 +30,39 @@ func newAlphaCommand() *cobra.Command {
 		Example: `
 # scaffolds webhook server
 kubebuilder alpha webhook <params>
+
+# writes the built-in templates to a directory for customization
+kubebuilder alpha dump-templates --output templates
+
+# checks the project for problems
+kubebuilder alpha doctor
+
+# lists the scaffolded files and whether they were changed
+kubebuilder alpha status
+
+# lists the resources of the project and what was scaffolded for them
+kubebuilder alpha list
+
+# merges the latest templates into the scaffolded project files
+kubebuilder alpha rescaffold
+
+# renames the kind, group or version of an API
+kubebuilder alpha rename --group ship --version v1 --kind Frigate --new-kind Corvette
+
+# scaffolds the project again from PROJECT into a new directory
+kubebuilder alpha regenerate --output ../regenerated
+
+# migrates a version 1 project to the v2 layout
+kubebuilder alpha migrate
 `,
 	}
 
 	cmd.AddCommand(
-		newWebhookCmd(),
+		newDumpTemplatesCmd(),
+		newStatusCmd(),
+		newDoctorCmd(),
 	)
+
+	cmd.AddCommand(newCmds(layout.alpha)...)
 	return cmd
 }
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+)
+
+// answerAnnotation marks the flags answering the questions of the commands,
+// which can also be set by their KUBEBUILDER_* environment variable
+const answerAnnotation = "kubebuilder-answer"
+
+// bindInteractionFlags adds the flags controlling the questions to the
+// persistent flags of the root command cmd, and sets the answer flags from
+// the environment and the flags of the user configuration before the
+// commands run.
+func bindInteractionFlags(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	f.BoolVar(&util.NonInteractive, "non-interactive", false,
+		"if set, fail instead of prompting for the answers which are not given by flags or environment variables")
+	f.BoolVarP(&util.AssumeYes, "yes", "y", false,
+		"if set, answer yes to the questions which are not answered by flags or environment variables")
+	markAnswerFlags(f, "non-interactive", "yes")
+
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := setAnswersFromEnv(cmd.Flags()); err != nil {
+			return withKind(kindInvalidFlags, err)
+		}
+		return withKind(kindInvalidFlags, setFlagsFromConfig(cmd.Flags()))
+	}
+}
+
+// markAnswerFlags marks the flags names of f as answers of questions, see
+// setAnswersFromEnv.
+func markAnswerFlags(f *flag.FlagSet, names ...string) {
+	for _, name := range names {
+		// SetAnnotation only fails for flags which are not defined
+		if err := f.SetAnnotation(name, answerAnnotation, []string{"true"}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// setAnswersFromEnv sets the answer flags of f which are not set on the
+// command line from their environment variable, e.g. --fetch-deps from
+// KUBEBUILDER_FETCH_DEPS.
+func setAnswersFromEnv(f *flag.FlagSet) error {
+	var err error
+	f.VisitAll(func(fl *flag.Flag) {
+		if err != nil || fl.Changed || fl.Annotations[answerAnnotation] == nil {
+			return
+		}
+		name := util.EnvName(fl.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if e := f.Set(fl.Name, value); e != nil {
+			err = fmt.Errorf("invalid %s: %v", name, e)
+		}
+	})
+	return err
+}
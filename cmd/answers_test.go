@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+)
+
+func TestSetAnswersFromEnv(t *testing.T) {
+	var resource, controller bool
+	var version string
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolVar(&resource, "resource", true, "")
+	cmd.Flags().BoolVar(&controller, "controller", true, "")
+	cmd.Flags().StringVar(&version, "version", "", "")
+	markAnswerFlags(cmd.Flags(), "resource", "controller")
+
+	for name, value := range map[string]string{
+		"KUBEBUILDER_RESOURCE":   "false",
+		"KUBEBUILDER_CONTROLLER": "false",
+		"KUBEBUILDER_VERSION":    "v1",
+	} {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+	if err := cmd.ParseFlags([]string{"--controller"}); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := setAnswersFromEnv(cmd.Flags()); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	// the flags set on the command line win, and only answers are set
+	if resource || !controller || version != "" {
+		t.Errorf("got resource=%v controller=%v version=%q", resource, controller, version)
+	}
+	if !cmd.Flag("resource").Changed {
+		t.Errorf("expected --resource to be answered")
+	}
+
+	os.Setenv("KUBEBUILDER_RESOURCE", "maybe")
+	cmd.Flag("resource").Changed = false
+	err := setAnswersFromEnv(cmd.Flags())
+	if err == nil || err.Error() != `invalid KUBEBUILDER_RESOURCE: invalid argument "maybe" for "--resource" flag: `+
+		`strconv.ParseBool: parsing "maybe": invalid syntax` {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestAsk(t *testing.T) {
+	defer func() { util.AssumeYes, util.NonInteractive = false, false }()
+
+	util.AssumeYes, util.NonInteractive = true, true
+	if yes, err := util.Ask("Create Resource", "resource"); err != nil || !yes {
+		t.Errorf("expected yes, got %v", err)
+	}
+
+	util.AssumeYes = false
+	_, err := util.Ask("Create Resource", "resource")
+	if err == nil || err.Error() != "Create Resource: no answer in non-interactive mode, "+
+		"set --resource or KUBEBUILDER_RESOURCE" {
+		t.Errorf("got error %v", err)
+	}
+}
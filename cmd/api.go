@@ -17,9 +17,7 @@ limitations under the License.
 package main
 
 import (
-	"bufio"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 
@@ -37,6 +35,9 @@ type apiOptions struct {
 
 	// runMake indicates whether to run make or not after scaffolding APIs
 	runMake bool
+
+	dryRun dryRunOptions
+	output outputOptions
 }
 
 func (o *apiOptions) bindCmdFlags(cmd *cobra.Command) {
@@ -48,7 +49,10 @@ func (o *apiOptions) bindCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.apiScaffolder.DoController, "controller", true,
 		"if set, generate the controller without prompting the user")
 	o.controllerFlag = cmd.Flag("controller")
+	markAnswerFlags(cmd.Flags(), "resource", "controller")
 	o.apiScaffolder.Resource = resourceForFlags(cmd.Flags())
+	o.dryRun.bindFlags(cmd.Flags())
+	o.output.bindFlags(cmd.Flags())
 }
 
 // resourceForFlags registers flags for Resource fields and returns the Resource
@@ -64,44 +68,55 @@ func resourceForFlags(f *flag.FlagSet) *resource.Resource {
 }
 
 // APICmd represents the resource command
-func (o *apiOptions) runAddAPI() {
-	dieIfNoProject()
+func (o *apiOptions) runAddAPI() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
 
-	reader := bufio.NewReader(os.Stdin)
+	var err error
 	if !o.resourceFlag.Changed {
-		fmt.Println("Create Resource [y/n]")
-		o.apiScaffolder.DoResource = util.Yesno(reader)
+		if o.apiScaffolder.DoResource, err = util.Ask("Create Resource", "resource"); err != nil {
+			return withKind(kindMissingAnswer, err)
+		}
 	}
 
 	if !o.controllerFlag.Changed {
-		fmt.Println("Create Controller [y/n]")
-		o.apiScaffolder.DoController = util.Yesno(reader)
+		if o.apiScaffolder.DoController, err = util.Ask("Create Controller", "controller"); err != nil {
+			return withKind(kindMissingAnswer, err)
+		}
 	}
 
 	if err := o.apiScaffolder.Validate(); err != nil {
-		log.Fatalln(err)
+		return withKind(kindInvalidResource, err)
+	}
+	if !o.apiScaffolder.DoResource && !o.apiScaffolder.DoController {
+		o.output.warn("neither the resource nor the controller is created")
 	}
 
 	fmt.Println("Writing scaffold for you to edit...")
 
-	if err := o.apiScaffolder.Scaffold(); err != nil {
-		log.Fatal(err)
+	changes, err := o.dryRun.run(o.apiScaffolder.Scaffold)
+	if err != nil {
+		return err
 	}
+	o.output.recordChanges(changes, o.dryRun.dryRun)
 
-	if err := o.postScaffold(); err != nil {
-		log.Fatal(err)
+	if o.dryRun.dryRun {
+		return nil
 	}
+	printPaths(o.apiScaffolder.EditPaths)
+
+	return o.postScaffold()
 }
 
 func (o *apiOptions) postScaffold() error {
 	if o.runMake {
 		fmt.Println("Running make...")
-		cm := exec.Command("make") // #nosec
-		cm.Stderr = os.Stderr
-		cm.Stdout = os.Stdout
-		if err := cm.Run(); err != nil {
-			return fmt.Errorf("error running make: %v", err)
-		}
+		return util.RunCommand(exec.Command("make")) // #nosec
 	}
 	return nil
 }
@@ -119,8 +134,14 @@ func newAPICommand() *cobra.Command {
 create resource will prompt the user for if it should scaffold the Resource and / or Controller.  To only
 scaffold a Controller for an existing Resource, select "n" for Resource.  To only define
 the schema for a Resource without writing a Controller, select "n" for Controller.
+The answers can also be given by --resource and --controller, or by KUBEBUILDER_RESOURCE
+and KUBEBUILDER_CONTROLLER.
 
 After the scaffold is written, api will run make on the project.
+
+With --output json the result is written to stdout as a JSON object with the files created and
+modified, the scaffold markers code was inserted at, the commands run, the warnings and the error
+with its code, and the other output goes to stderr.
 `,
 		Example: `	# Create a frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
 	kubebuilder create api --group ship --version v1beta1 --kind Frigate
@@ -139,9 +160,15 @@ After the scaffold is written, api will run make on the project.
 
 	# Regenerate code and run against the Kubernetes cluster configured by ~/.kube/config
 	make run
+
+	# Preview the files that would be written without writing them
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --dry-run
+
+	# Print what was written and run as JSON, e.g. for tools wrapping kubebuilder
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --resource --controller --output json
 `,
-		Run: func(cmd *cobra.Command, args []string) {
-			options.runAddAPI()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.output.run(options.runAddAPI)
 		},
 	}
 
@@ -150,9 +177,12 @@ After the scaffold is written, api will run make on the project.
 	return apiCmd
 }
 
-// dieIfNoProject checks to make sure the command is run from a directory containing a project file.
-func dieIfNoProject() {
+// checkProject returns an error if the command is not run from a directory
+// containing a project file.
+func checkProject() error {
 	if _, err := os.Stat("PROJECT"); os.IsNotExist(err) {
-		log.Fatalf("Command must be run from a directory containing %s", "PROJECT")
+		return withKind(kindProjectNotFound,
+			fmt.Errorf("Command must be run from a directory containing %s", "PROJECT"))
 	}
+	return nil
 }
@@ -0,0 +1,266 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// the tests do not read the user configuration
+	dir, err := ioutil.TempDir("", "kubebuilder-config-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Unsetenv(configEnv)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// execute runs kubebuilder with args in dir and returns the error of the
+// command.
+func execute(t *testing.T, dir string, args ...string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.Chdir(wd) // nolint: errcheck
+
+	// the flag errors look up the output format in the command line
+	osArgs := os.Args
+	os.Args = append([]string{"kubebuilder"}, args...)
+	defer func() { os.Args = osArgs }()
+
+	cmd, err := newRootCmd()
+	if err != nil {
+		return err
+	}
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+// initProject returns a new directory with a project initialized by
+// kubebuilder init.
+func initProject(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "kubebuilder-cmd-")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	err = execute(t, dir, "init", "--domain", "example.com", "--repo", "example.com/project",
+		"--skip-go-version-check", "--fetch-deps=false")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("error initializing the project: %v", err)
+	}
+	return dir
+}
+
+// replaceInFile replaces old by new in the file at path.
+func replaceInFile(t *testing.T, path, old, new string) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(strings.Replace(string(b), old, new, -1)), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	createAPI := []string{"create", "api", "--group", "ship", "--version", "v1", "--kind", "Frigate",
+		"--resource", "--controller", "--make=false"}
+
+	for _, c := range []struct {
+		name string
+
+		// noProject runs the command outside of a project
+		noProject bool
+
+		// setup prepares the project in dir
+		setup func(t *testing.T, dir string)
+
+		args []string
+
+		// kind is the kind of the expected error, nil for no error
+		kind *errorKind
+	}{
+		{
+			name: "create api",
+			args: createAPI,
+		},
+		{
+			name:      "create api outside of a project",
+			noProject: true,
+			args:      createAPI,
+			kind:      &kindProjectNotFound,
+		},
+		{
+			name: "unknown flag",
+			args: append(createAPI, "--frigate"),
+			kind: &kindInvalidFlags,
+		},
+		{
+			name: "invalid dry-run format",
+			args: append(createAPI, "--dry-run", "--dry-run-format", "yaml"),
+			kind: &kindInvalidFlags,
+		},
+		{
+			name: "invalid answer variable",
+			setup: func(t *testing.T, dir string) {
+				os.Setenv("KUBEBUILDER_CONTROLLER", "maybe")
+			},
+			args: []string{"create", "api", "--group", "ship", "--version", "v1", "--kind", "Frigate",
+				"--resource", "--make=false"},
+			kind: &kindInvalidFlags,
+		},
+		{
+			name:      "unknown license",
+			noProject: true,
+			args: []string{"init", "--domain", "example.com", "--license", "WTFPL",
+				"--skip-go-version-check", "--fetch-deps=false"},
+			kind: &kindInvalidFlags,
+		},
+		{
+			name: "invalid group",
+			args: []string{"create", "api", "--group", "Ship", "--version", "v1", "--kind", "Frigate",
+				"--resource", "--controller", "--make=false"},
+			kind: &kindInvalidResource,
+		},
+		{
+			name: "webhook of an invalid kind",
+			args: []string{"create", "webhook", "--group", "ship", "--version", "v1", "--kind", "frigate",
+				"--defaulting"},
+			kind: &kindInvalidResource,
+		},
+		{
+			name: "api which already exists",
+			setup: func(t *testing.T, dir string) {
+				if err := execute(t, dir, createAPI...); err != nil {
+					t.Fatalf("error %v", err)
+				}
+			},
+			args: createAPI,
+			kind: &kindAlreadyExists,
+		},
+		{
+			name: "initialized project",
+			args: []string{"init", "--domain", "example.com", "--skip-go-version-check", "--fetch-deps=false"},
+			kind: &kindAlreadyExists,
+		},
+		{
+			name: "missing scheme marker",
+			setup: func(t *testing.T, dir string) {
+				replaceInFile(t, filepath.Join(dir, "main.go"), "// +kubebuilder:scaffold:scheme", "")
+			},
+			args: createAPI,
+			kind: &kindMarkerMissing,
+		},
+		{
+			name: "question in non-interactive mode",
+			args: []string{"create", "api", "--group", "ship", "--version", "v1", "--kind", "Frigate",
+				"--make=false", "--non-interactive"},
+			kind: &kindMissingAnswer,
+		},
+		{
+			name: "headers up to date",
+			args: []string{"alpha", "update-headers", "--check"},
+		},
+		{
+			name: "outdated headers",
+			setup: func(t *testing.T, dir string) {
+				replaceInFile(t, filepath.Join(dir, "hack", "boilerplate.go.txt"), "Licensed", "Licenced")
+			},
+			args: []string{"alpha", "update-headers", "--check"},
+			kind: &kindFailed,
+		},
+		{
+			name: "failing make",
+			setup: func(t *testing.T, dir string) {
+				bin := filepath.Join(dir, "bin")
+				if err := os.Mkdir(bin, 0700); err != nil {
+					t.Fatalf("error %v", err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(bin, "make"), []byte("#!/bin/sh\nexit 2\n"), 0700); err != nil {
+					t.Fatalf("error %v", err)
+				}
+				os.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+			},
+			args: []string{"create", "api", "--group", "ship", "--version", "v1", "--kind", "Frigate",
+				"--resource", "--controller"},
+			kind: &kindExternalToolFailed,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			defer restoreEnv("PATH", "KUBEBUILDER_CONTROLLER")()
+
+			var dir string
+			if c.noProject {
+				var err error
+				if dir, err = ioutil.TempDir("", "kubebuilder-cmd-"); err != nil {
+					t.Fatalf("error %v", err)
+				}
+			} else {
+				dir = initProject(t)
+			}
+			defer os.RemoveAll(dir)
+			if c.setup != nil {
+				c.setup(t, dir)
+			}
+
+			err := execute(t, dir, c.args...)
+			switch {
+			case c.kind == nil && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case c.kind != nil && err == nil:
+				t.Errorf("expected a %s error", c.kind.code)
+			case c.kind != nil && kindOf(err) != *c.kind:
+				t.Errorf("expected a %s error, got %s: %v", c.kind.code, kindOf(err).code, err)
+			}
+		})
+	}
+}
+
+// restoreEnv returns a function restoring the environment variables names
+// to their current value.
+func restoreEnv(names ...string) func() {
+	values := map[string]*string{}
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			values[name] = &v
+		} else {
+			values[name] = nil
+		}
+	}
+	return func() {
+		for name, v := range values {
+			if v == nil {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, *v)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// configEnv is the environment variable overriding the path of the user
+// configuration file
+const configEnv = "KUBEBUILDER_CONFIG"
+
+// configAnnotation marks the flags defaulting to a value of the user
+// configuration file, the annotation is the key of the value
+const configAnnotation = "kubebuilder-config"
+
+// configKeys are the keys of the user configuration file, in the order they
+// are listed by 'config view'
+var configKeys = []string{
+	"domain",
+	"owner",
+	"license",
+	"licenseFile",
+	"boilerplatePath",
+	"projectVersion",
+	"image",
+	"namespacePrefix",
+	"templateDir",
+}
+
+// userConfig is the user configuration file, which gives the defaults of
+// the flags of the commands
+type userConfig struct {
+	// path is the path of the file, empty without file
+	path string
+
+	// values are the values of the file by key
+	values map[string]string
+}
+
+// userConfigPath returns the path of the user configuration file, empty if
+// it cannot be determined, and whether it is given by KUBEBUILDER_CONFIG.
+func userConfigPath() (string, bool) {
+	if path := os.Getenv(configEnv); path != "" {
+		return path, true
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home := os.Getenv("HOME")
+		if home == "" {
+			return "", false
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kubebuilder", "config.yaml"), false
+}
+
+// loadUserConfig reads the user configuration file. Without file the
+// configuration is empty, unless its path is given by KUBEBUILDER_CONFIG.
+func loadUserConfig() (*userConfig, error) {
+	c := &userConfig{values: map[string]string{}}
+	path, explicit := userConfigPath()
+	if path == "" {
+		return c, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the user configuration: %v", err)
+	}
+
+	c.path = path
+	if err := yaml.Unmarshal(b, &c.values); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	if c.values == nil {
+		c.values = map[string]string{}
+	}
+	for key, value := range c.values {
+		if !isConfigKey(key) {
+			return nil, fmt.Errorf("unknown key %q in %s, the keys are %s",
+				key, path, strings.Join(configKeys, ", "))
+		}
+		// the paths of files read by kubebuilder are relative to the
+		// configuration file
+		if (key == "templateDir" || key == "licenseFile") && value != "" && !filepath.IsAbs(value) {
+			c.values[key] = filepath.Join(filepath.Dir(path), value)
+		}
+	}
+	return c, nil
+}
+
+func isConfigKey(key string) bool {
+	for _, k := range configKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// markConfigFlag marks the flag name of f as defaulting to the value of key
+// in the user configuration file, see setFlagsFromConfig.
+func markConfigFlag(f *flag.FlagSet, name, key string) {
+	// SetAnnotation only fails for flags which are not defined
+	if err := f.SetAnnotation(name, configAnnotation, []string{key}); err != nil {
+		panic(err)
+	}
+}
+
+// setFlagsFromConfig sets the flags of f marked by markConfigFlag which are
+// not set on the command line from the user configuration file. The file is
+// only read for commands with such flags.
+func setFlagsFromConfig(f *flag.FlagSet) error {
+	var marked bool
+	f.VisitAll(func(fl *flag.Flag) {
+		marked = marked || fl.Annotations[configAnnotation] != nil
+	})
+	if !marked {
+		return nil
+	}
+
+	c, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	f.VisitAll(func(fl *flag.Flag) {
+		if err != nil || fl.Changed || fl.Annotations[configAnnotation] == nil {
+			return
+		}
+		key := fl.Annotations[configAnnotation][0]
+		value, ok := c.values[key]
+		if !ok {
+			return
+		}
+		if e := f.Set(fl.Name, value); e != nil {
+			err = fmt.Errorf("invalid %s in %s: %v", key, c.path, e)
+		}
+	})
+	return err
+}
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show the user configuration",
+		Long:  `Show the user configuration.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println("Coming soon.")
+		},
+	}
+	cmd.AddCommand(
+		newConfigViewCmd(),
+	)
+	return cmd
+}
+
+func newConfigViewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Show the defaults of the flags and where they come from",
+		Long: `Show the defaults of the flags of init and of the alpha commands, with the file they are read from.
+
+The defaults are read from the user configuration file, $XDG_CONFIG_HOME/kubebuilder/config.yaml
+or ~/.config/kubebuilder/config.yaml, or the file given by KUBEBUILDER_CONFIG, e.g. to share
+the configuration of an organization. The values which are not in the file are the built-in
+defaults. The flags set on the command line win over the file.
+
+The keys of the file are:
+
+  domain           --domain of init
+  owner            --owner of init
+  license          --license of init
+  licenseFile      --license-file of init, relative to the directory of the file
+  boilerplatePath  --path of init and alpha update-headers
+  projectVersion   --project-version of init
+  image            --image of init
+  namespacePrefix  --namespace-prefix of init
+  templateDir      --template-dir of init, relative to the directory of the file
+`,
+		Example: `	# Use example.com as domain and "The Example Authors" as owner by default
+	mkdir -p ~/.config/kubebuilder
+	cat > ~/.config/kubebuilder/config.yaml <<EOF
+	domain: example.com
+	owner: The Example Authors
+	EOF
+
+	# Show the defaults
+	kubebuilder config view
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return viewConfig(os.Stdout)
+		},
+	}
+}
+
+// viewConfig writes the value of each key of the user configuration to w,
+// with the file it is read from or default for built-in defaults.
+func viewConfig(w io.Writer) error {
+	c, err := loadUserConfig()
+	if err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+	defaults := configDefaults()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, key := range configKeys {
+		value, source := defaults[key], "default"
+		if v, ok := c.values[key]; ok {
+			value, source = v, c.path
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, value, source)
+	}
+	return tw.Flush()
+}
+
+// configDefaults returns the built-in defaults of the keys of the user
+// configuration, which are the defaults of the flags of init.
+func configDefaults() map[string]string {
+	defaults := map[string]string{}
+	newInitProjectCmd().Flags().VisitAll(func(fl *flag.Flag) {
+		if key := fl.Annotations[configAnnotation]; key != nil {
+			defaults[key[0]] = fl.DefValue
+		}
+	})
+	return defaults
+}
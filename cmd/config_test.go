@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content as the user configuration file of the test
+// and returns its path.
+func writeConfig(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	os.Setenv(configEnv, path)
+	return path
+}
+
+func TestInitWithUserConfig(t *testing.T) {
+	defer restoreEnv(configEnv)()
+	dir, err := ioutil.TempDir("", "kubebuilder-cmd-")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "header.txt"), []byte("// Copyright {{.Year}} {{.Owner}}."), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	writeConfig(t, dir, `domain: example.com
+owner: The Example Authors
+license: none
+licenseFile: header.txt
+image: example.com/frigate:v1
+namespacePrefix: frigate
+`)
+
+	// the flags win over the user configuration
+	err = execute(t, dir, "init", "--domain", "example.org", "--repo", "example.org/project",
+		"--skip-go-version-check", "--fetch-deps=false")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	for path, expected := range map[string]string{
+		"PROJECT":                           "domain: example.org",
+		"hack/boilerplate.go.txt":           "// Copyright " + strconv.Itoa(time.Now().Year()) + " The Example Authors.",
+		"Makefile":                          "IMG ?= example.com/frigate:v1",
+		"config/default/kustomization.yaml": "namespace: frigate-system",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatalf("error %v", err)
+		}
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("expected %s to contain %q, got:\n%s", path, expected, b)
+		}
+	}
+}
+
+func TestUserConfigErrors(t *testing.T) {
+	defer restoreEnv(configEnv)()
+	dir, err := ioutil.TempDir("", "kubebuilder-cmd-")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.RemoveAll(dir)
+	initArgs := []string{"init", "--skip-go-version-check", "--fetch-deps=false"}
+
+	for _, c := range []struct {
+		name, config, expected string
+	}{
+		{
+			name:     "unknown key",
+			config:   "owners: The Example Authors\n",
+			expected: `unknown key "owners" in ` + filepath.Join(dir, "config.yaml"),
+		},
+		{
+			name:     "invalid yaml",
+			config:   "domain: [example.com\n",
+			expected: "error parsing " + filepath.Join(dir, "config.yaml"),
+		},
+		{
+			name:     "unsupported project version",
+			config:   "projectVersion: 9\n",
+			expected: `unsupported project version "9"`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			writeConfig(t, dir, c.config)
+			err := execute(t, dir, initArgs...)
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected error %q, got %v", c.expected, err)
+			}
+			if kindOf(err) != kindInvalidFlags {
+				t.Errorf("expected an invalid-flags error, got %s", kindOf(err).code)
+			}
+		})
+	}
+
+	os.Setenv(configEnv, filepath.Join(dir, "missing.yaml"))
+	if err := execute(t, dir, initArgs...); err == nil {
+		t.Errorf("expected an error for a missing KUBEBUILDER_CONFIG")
+	}
+}
+
+func TestViewConfig(t *testing.T) {
+	defer restoreEnv(configEnv)()
+	dir, err := ioutil.TempDir("", "kubebuilder-cmd-")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, `domain: example.com
+projectVersion: 2
+templateDir: templates
+`)
+
+	out := &bytes.Buffer{}
+	if err := viewConfig(out); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	expected := []string{
+		"KEY VALUE SOURCE",
+		"domain example.com " + path,
+		"owner default",
+		"license apache2 default",
+		"licenseFile default",
+		"boilerplatePath default",
+		"projectVersion 2 " + path,
+		"image controller:latest default",
+		"namespacePrefix default",
+		"templateDir " + filepath.Join(dir, "templates") + " " + path,
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), out)
+	}
+}
@@ -22,7 +22,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newCreateCmd() *cobra.Command {
+func newCreateCmd(layout layoutCommands) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Scaffold a Kubernetes API or webhook.",
@@ -35,15 +35,7 @@ func newCreateCmd() *cobra.Command {
 		newAPICommand(),
 	)
 
-	foundProject, version := getProjectVersion()
-	// It add webhook v2 command in the following 2 cases:
-	// - There are no PROJECT file found.
-	// - version == 2 is found in the PROJECT file.
-	if !foundProject || version == "2" {
-		cmd.AddCommand(
-			newWebhookV2Cmd(),
-		)
-	}
+	cmd.AddCommand(newCmds(layout.create)...)
 
 	return cmd
 }
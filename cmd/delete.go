@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+func newDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Remove a scaffolded Kubernetes API.",
+		Long:  `Remove a scaffolded Kubernetes API.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println("Coming soon.")
+		},
+	}
+	cmd.AddCommand(
+		newDeleteAPICmd(),
+	)
+	return cmd
+}
+
+// deleteAPIOptions represents commandline options for removing an API.
+type deleteAPIOptions struct {
+	res *resource.Resource
+
+	dryRun dryRunOptions
+}
+
+func newDeleteAPICmd() *cobra.Command {
+	o := deleteAPIOptions{res: &resource.Resource{}}
+
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Remove a Kubernetes API with its controller and webhooks",
+		Long: `Remove a Kubernetes API with its controller and webhooks.
+
+delete api reverts 'kubebuilder create api' and 'kubebuilder create webhook': it
+removes the files scaffolded for the resource and its generated files, and the
+code wiring them in main.go, suite_test.go and config/crd/kustomization.yaml.
+The group version is only removed with its last resource. The changes are listed
+for confirmation before they are written.
+`,
+		Example: `	# Preview removing the frigates API of Group: ship, Version: v1beta1 and Kind: Frigate
+	kubebuilder delete api --group ship --version v1beta1 --kind Frigate --dry-run
+
+	# Remove it without confirmation
+	kubebuilder delete api --group ship --version v1beta1 --kind Frigate --yes
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	cmd.Flags().StringVar(&o.res.Kind, "kind", "", "resource Kind")
+	cmd.Flags().StringVar(&o.res.Group, "group", "", "resource Group")
+	cmd.Flags().StringVar(&o.res.Version, "version", "", "resource Version")
+	o.dryRun.bindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *deleteAPIOptions) run() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+
+	api := &scaffold.API{Resource: o.res}
+	if err := api.Validate(); err != nil {
+		return withKind(kindInvalidResource, err)
+	}
+
+	overlay, err := filesystem.Stage(api.Delete)
+	if err != nil {
+		return err
+	}
+	if o.dryRun.dryRun {
+		return o.dryRun.print(os.Stdout, overlay)
+	}
+
+	if !util.AssumeYes {
+		fmt.Println("The following files will be changed:")
+		if err := (&dryRunOptions{format: "files"}).print(os.Stdout, overlay); err != nil {
+			return err
+		}
+	}
+	confirmed, err := util.Ask("Delete the API", "yes")
+	if err != nil {
+		return withKind(kindMissingAnswer, err)
+	}
+	if !confirmed {
+		fmt.Println("Nothing was changed.")
+		return nil
+	}
+
+	return overlay.Commit()
+}
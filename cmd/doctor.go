@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/doctor"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the project for problems",
+		Long: `Check the project in the current directory for problems that break scaffolding or building it,
+and suggest how to fix them.
+
+The checks cover the PROJECT file, hack/boilerplate.go.txt, the tools used by the Makefile, the
+comments kubebuilder inserts code at in main.go and controllers/suite_test.go, and whether the
+resources in PROJECT match the types in api/.
+
+The command exits with a non-zero status if an error was found, warnings are only reported.
+`,
+		Example: `	# Check the project
+	kubebuilder alpha doctor
+
+	# Check the project and print the problems as JSON
+	kubebuilder alpha doctor --output json
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "text" && output != "json" {
+				return withKind(kindInvalidFlags, fmt.Errorf("unknown output format %q, must be one of text,json", output))
+			}
+
+			problems := doctor.Check()
+			if err := printProblems(os.Stdout, output, problems); err != nil {
+				return err
+			}
+			if doctor.HasErrors(problems) {
+				// the problems are reported above
+				return &reportedError{err: fmt.Errorf("the project has problems")}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "output format.  May be one of text,json")
+	return cmd
+}
+
+// printProblems writes the problems to w in the given format.
+func printProblems(w io.Writer, format string, problems []doctor.Problem) error {
+	if format == "json" {
+		if problems == nil {
+			problems = []doctor.Problem{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Problems []doctor.Problem `json:"problems"`
+		}{problems})
+	}
+
+	if len(problems) == 0 {
+		_, err := fmt.Fprintln(w, "No problems found.")
+		return err
+	}
+	for _, p := range problems {
+		_, err := fmt.Fprintf(w, "%-8s %s\n         fix: %s\n", strings.ToUpper(string(p.Severity)), p.Message, p.Fix)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
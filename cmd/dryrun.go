@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+// dryRunOptions represents commandline options for previewing the changes
+// of a scaffolding command.
+type dryRunOptions struct {
+	dryRun bool
+
+	// format is the output format of a dry-run, one of diff,files
+	format string
+}
+
+func (o *dryRunOptions) bindFlags(f *flag.FlagSet) {
+	f.BoolVar(&o.dryRun, "dry-run", false,
+		"if set, print the changes instead of writing them")
+	f.StringVar(&o.format, "dry-run-format", "diff",
+		"output format for --dry-run.  May be one of diff,files")
+}
+
+func (o *dryRunOptions) validate() error {
+	switch o.format {
+	case "diff", "files":
+		return nil
+	default:
+		return fmt.Errorf("unknown dry-run format %q, must be one of diff,files", o.format)
+	}
+}
+
+// run runs the scaffolding in fn as a single transaction and returns the
+// resulting changes. In dry-run mode the changes are printed instead of
+// written.
+func (o *dryRunOptions) run(fn func() error) ([]filesystem.Change, error) {
+	overlay, err := filesystem.Stage(fn)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := overlay.Changes()
+	if err != nil {
+		return nil, err
+	}
+	if o.dryRun {
+		return changes, o.print(os.Stdout, overlay)
+	}
+	return changes, overlay.Commit()
+}
+
+// printPaths prints the paths of the files written for the user to edit,
+// only once they are committed.
+func printPaths(paths []string) {
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+}
+
+// print writes the changes in overlay to w in the selected format.
+func (o *dryRunOptions) print(w io.Writer, overlay *filesystem.Overlay) error {
+	if o.format == "diff" {
+		return overlay.Diff(w)
+	}
+
+	changes, err := overlay.Changes()
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		action := "modify"
+		switch {
+		case c.Created():
+			action = "create"
+		case c.Removed():
+			action = "delete"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", action, c.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printMoves writes the changes in overlay to w like the files format, with
+// the moved files, by their new path, as renames.
+func printMoves(w io.Writer, overlay *filesystem.Overlay, moved map[string]string) error {
+	changes, err := overlay.Changes()
+	if err != nil {
+		return err
+	}
+	movedFrom := map[string]bool{}
+	for _, from := range moved {
+		movedFrom[from] = true
+	}
+	for _, c := range changes {
+		var line string
+		switch from, ok := moved[c.Path]; {
+		case ok:
+			line = fmt.Sprintf("rename %s -> %s", from, c.Path)
+		case movedFrom[c.Path]:
+			continue
+		case c.Created():
+			line = "create " + c.Path
+		case c.Removed():
+			line = "delete " + c.Path
+		default:
+			line = "modify " + c.Path
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+// errorKind is the kind of an error of a command, which sets the exit status
+// of kubebuilder and the code of the error in the json output
+type errorKind struct {
+	code     string
+	exitCode int
+}
+
+// The kinds of the errors of the commands. Their codes and exit statuses are
+// part of the interface of kubebuilder and must not change.
+var (
+	// kindFailed is the kind of the errors without a more specific kind
+	kindFailed = errorKind{code: "scaffold-failed", exitCode: 1}
+
+	// kindInvalidFlags is the kind of unknown flags and flags with invalid
+	// values
+	kindInvalidFlags = errorKind{code: "invalid-flags", exitCode: 2}
+
+	// kindProjectNotFound is the kind of commands run outside of a project
+	kindProjectNotFound = errorKind{code: "project-not-found", exitCode: 3}
+
+	// kindInvalidResource is the kind of an invalid group, version or kind
+	kindInvalidResource = errorKind{code: "invalid-resource", exitCode: 4}
+
+	// kindAlreadyExists is the kind of scaffolding a file or a project which
+	// already exists
+	kindAlreadyExists = errorKind{code: "already-exists", exitCode: 5}
+
+	// kindMarkerMissing is the kind of a scaffold marker which cannot be
+	// found in the file code is inserted in
+	kindMarkerMissing = errorKind{code: "marker-missing", exitCode: 6}
+
+	// kindMissingAnswer is the kind of a question which cannot be asked, see
+	// util.Ask
+	kindMissingAnswer = errorKind{code: "missing-answer", exitCode: 7}
+
+	// kindExternalToolFailed is the kind of a command run by kubebuilder,
+	// e.g. make, which failed
+	kindExternalToolFailed = errorKind{code: "external-tool-failed", exitCode: 8}
+)
+
+// kindError is an error with its kind
+type kindError struct {
+	kind errorKind
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+// withKind returns err with the kind, unless it already has one.
+func withKind(kind errorKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*kindError); ok {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// reportedError is an error already reported to the user, e.g. in the json
+// output, which only sets the exit status
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+// kindOf returns the kind of err. The errors of the scaffolding and of the
+// commands run have their kind without being set with withKind.
+func kindOf(err error) errorKind {
+	switch e := err.(type) {
+	case *kindError:
+		return e.kind
+	case *reportedError:
+		return kindOf(e.err)
+	case *util.CommandError:
+		return kindExternalToolFailed
+	}
+	switch {
+	case scaffold.IsAlreadyExists(err):
+		return kindAlreadyExists
+	case scaffold.IsMarkerMissing(err):
+		return kindMarkerMissing
+	}
+	return kindFailed
+}
+
+// exitCode reports err unless it is already reported, and returns the exit
+// status of kubebuilder for it, 0 for nil.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if _, reported := err.(*reportedError); !reported {
+		log.Print(err)
+	}
+	return kindOf(err).exitCode
+}
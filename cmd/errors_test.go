@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+)
+
+func TestKindOf(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		kind errorKind
+	}{
+		{withKind(kindInvalidResource, fmt.Errorf("kind cannot be empty")), kindInvalidResource},
+		{withKind(kindFailed, withKind(kindAlreadyExists, fmt.Errorf("exists"))), kindAlreadyExists},
+		{&reportedError{err: withKind(kindProjectNotFound, fmt.Errorf("no PROJECT"))}, kindProjectNotFound},
+		{&util.CommandError{Command: "make", Err: &exec.ExitError{}}, kindExternalToolFailed},
+		{fmt.Errorf("failed"), kindFailed},
+	} {
+		if kind := kindOf(c.err); kind != c.kind {
+			t.Errorf("kindOf(%v) = %s, expected %s", c.err, kind.code, c.kind.code)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	kinds := []errorKind{
+		kindFailed, kindInvalidFlags, kindProjectNotFound, kindInvalidResource,
+		kindAlreadyExists, kindMarkerMissing, kindMissingAnswer, kindExternalToolFailed,
+	}
+	codes := map[string]bool{}
+	exitCodes := map[int]bool{}
+	for _, k := range kinds {
+		if codes[k.code] || exitCodes[k.exitCode] || k.exitCode == 0 {
+			t.Errorf("the code %s or the exit status %d of a kind is not unique", k.code, k.exitCode)
+		}
+		codes[k.code], exitCodes[k.exitCode] = true, true
+	}
+	if code := exitCode(nil); code != 0 {
+		t.Errorf("expected exit status 0 without error, got %d", code)
+	}
+}
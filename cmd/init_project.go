@@ -18,7 +18,7 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"regexp"
@@ -51,13 +51,27 @@ Writes the following files:
 - a Patch file for enabling prometheus metrics
 - a cmd/manager/main.go to run
 
-project will prompt the user to run 'dep ensure' after writing the project files.
+project will prompt the user to run 'dep ensure' after writing the project files, unless
+--fetch-deps or KUBEBUILDER_FETCH_DEPS is set.
+
+The flags which are not set default to the values of the user configuration file, see
+'kubebuilder config view'.
+
+With --output json the files written, the commands run, the warnings and the error with its code
+are written to stdout as a JSON object, and the other output goes to stderr.
 `,
 		Example: `# Scaffold a project using the apache2 license with "The Kubernetes authors" as owners
 kubebuilder init --domain example.org --license apache2 --owner "The Kubernetes authors"
+
+# Scaffold a project using the MIT license, given by its SPDX identifier
+kubebuilder init --domain example.org --license MIT --owner "The Example Authors"
+
+# Scaffold a project using a custom license header, e.g. containing
+# "Copyright {{.Year}} {{.Owner}}."
+kubebuilder init --domain example.org --license-file header.txt --owner "The Example Authors"
 `,
-		Run: func(cmd *cobra.Command, args []string) {
-			o.initializeProject()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.output.run(o.initializeProject)
 		},
 	}
 
@@ -70,10 +84,17 @@ type projectOptions struct {
 
 	// flags
 	fetchDeps          bool
+	fetchDepsFlag      *flag.Flag
 	skipGoVersionCheck bool
 
 	boilerplate project.Boilerplate
 	project project.Project
+	licenseFile string
+	image       string
+	namePrefix  string
+
+	dryRun dryRunOptions
+	output outputOptions
 
 	// deprecated flags
 	dep                bool
@@ -89,6 +110,8 @@ func (o *projectOptions) bindCmdlineFlags(cmd *cobra.Command) {
 
 	// dependency args
 	cmd.Flags().BoolVar(&o.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded")
+	o.fetchDepsFlag = cmd.Flag("fetch-deps")
+	markAnswerFlags(cmd.Flags(), "fetch-deps")
 
 	// deprecated dependency args
 	cmd.Flags().BoolVar(&o.dep, "dep", true, "if specified, determines whether dep will be used.")
@@ -99,68 +122,123 @@ func (o *projectOptions) bindCmdlineFlags(cmd *cobra.Command) {
 
 	// boilerplate args
 	cmd.Flags().StringVar(&o.boilerplate.Path, "path", "", "path for boilerplate")
-	cmd.Flags().StringVar(&o.boilerplate.License, "license", "apache2", "license to use to boilerplate.  May be one of apache2,none")
+	cmd.Flags().StringVar(&o.boilerplate.License, "license", "apache2", "license to use to boilerplate.  May be one of "+
+		strings.Join(project.Licenses(), ",")+",apache2,none")
+	cmd.Flags().StringVar(&o.licenseFile, "license-file", "", "file with the license header template to use to "+
+		"boilerplate, which can use {{.Owner}} and {{.Year}}.  overrides --license.")
 	cmd.Flags().StringVar(&o.boilerplate.Owner, "owner", "", "Owner to add to the copyright")
+	markConfigFlag(cmd.Flags(), "path", "boilerplatePath")
+	markConfigFlag(cmd.Flags(), "license", "license")
+	markConfigFlag(cmd.Flags(), "license-file", "licenseFile")
+	markConfigFlag(cmd.Flags(), "owner", "owner")
 
 	// project args
 	cmd.Flags().StringVar(&o.project.Repo, "repo", util.Repo, "name of the github repo.  "+
 		"defaults to the go package of the current working directory.")
 	cmd.Flags().StringVar(&o.project.Domain, "domain", "k8s.io", "domain for groups")
-	cmd.Flags().StringVar(&o.project.Version, "project-version", project.Version2, "project version")
+	cmd.Flags().StringVar(&o.project.Version, "project-version", scaffold.DefaultVersion,
+		"project version.  May be one of "+strings.Join(scaffold.LayoutVersions(), ","))
+	cmd.Flags().BoolVar(&o.project.MultiGroup, "multigroup", false, "if set, put the APIs in api/<group>/<version> "+
+		"and the controllers in controllers/<group> so the project can have more than one group")
+	cmd.Flags().StringVar(&o.project.TemplateDir, "template-dir", "", "directory with templates overriding the built-in ones.  "+
+		"Use 'kubebuilder alpha dump-templates' to get the built-in templates as a starting point.")
+	cmd.Flags().StringVar(&o.image, "image", "controller:latest", "image of the controller manager")
+	cmd.Flags().StringVar(&o.namePrefix, "namespace-prefix", "", "prefix of the names of the resources and "+
+		"of their namespace <prefix>-system.  defaults to the name of the project directory.")
+	markConfigFlag(cmd.Flags(), "domain", "domain")
+	markConfigFlag(cmd.Flags(), "project-version", "projectVersion")
+	markConfigFlag(cmd.Flags(), "template-dir", "templateDir")
+	markConfigFlag(cmd.Flags(), "image", "image")
+	markConfigFlag(cmd.Flags(), "namespace-prefix", "namespacePrefix")
+
+	o.dryRun.bindFlags(cmd.Flags())
+	o.output.bindFlags(cmd.Flags())
 }
 
-func (o *projectOptions) initializeProject() {
+func (o *projectOptions) initializeProject() error {
 	if err := o.validate(); err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if o.depFlag.Changed {
+		o.output.warn("--dep is deprecated, use --fetch-deps instead")
+	}
+	if len(o.depArgs) > 0 {
+		o.output.warn("--depArgs is deprecated, it will be removed with version 1 scaffolding")
 	}
 
-	if err := o.scaffolder.Scaffold(); err != nil {
-		log.Fatalf("error scaffolding project: %v", err)
+	changes, err := o.dryRun.run(o.scaffolder.Scaffold)
+	if err != nil {
+		return withKind(kindOf(err), fmt.Errorf("error scaffolding project: %v", err))
+	}
+	o.output.recordChanges(changes, o.dryRun.dryRun)
+
+	if o.dryRun.dryRun {
+		return nil
 	}
 
 	if err := o.postScaffold(); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	fmt.Printf("Next: Define a resource with:\n" +
 		"$ kubebuilder create api\n")
+	return nil
 }
 
 func (o *projectOptions) validate() error {
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+
 	if !o.skipGoVersionCheck {
 		if err := validateGoVersion(); err != nil {
 			return err
 		}
 	}
 
-	switch o.project.Version {
-	case project.Version1:
-		var defEnsure *bool
-		if o.depFlag.Changed {
-			defEnsure = &o.dep
-		}
-		o.scaffolder = &scaffold.V1Project{
-			Project: o.project,
-			Boilerplate: o.boilerplate,
-
-			DepArgs: o.depArgs,
-			DefinitelyEnsure: defEnsure,
-		}
-	case project.Version2:
-		o.scaffolder = &scaffold.V2Project{
-			Project: o.project,
-			Boilerplate: o.boilerplate,
+	layout, err := scaffold.GetLayout(o.project.Version)
+	if err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+	if o.licenseFile != "" {
+		b, err := ioutil.ReadFile(o.licenseFile)
+		if err != nil {
+			return withKind(kindInvalidFlags, fmt.Errorf("error reading the license file: %v", err))
 		}
-	default:
-		return fmt.Errorf("unknown project version %v", o.project.Version)
+		o.boilerplate.LicenseTemplate = string(b)
+	}
+	if err := o.boilerplate.Validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
 	}
+	var defEnsure *bool
+	switch {
+	case o.depFlag.Changed:
+		defEnsure = &o.dep
+	case o.fetchDepsFlag.Changed:
+		defEnsure = &o.fetchDeps
+	}
+	o.scaffolder = layout.NewProject(scaffold.ProjectOptions{
+		Project:     o.project,
+		Boilerplate: o.boilerplate,
+		Image:       o.image,
+		NamePrefix:  o.namePrefix,
+
+		DepArgs:          o.depArgs,
+		DefinitelyEnsure: defEnsure,
+	})
 
 	if err := o.scaffolder.Validate(); err != nil {
 		return err
 	}
 
+	if o.project.TemplateDir != "" {
+		if fi, err := os.Stat(o.project.TemplateDir); err != nil || !fi.IsDir() {
+			return withKind(kindInvalidFlags, fmt.Errorf("template directory %s does not exist", o.project.TemplateDir))
+		}
+	}
+
 	if util.ProjectExist() {
-		return fmt.Errorf("Failed to initialize project because project is already initialized")
+		return withKind(kindAlreadyExists, fmt.Errorf("Failed to initialize project because project is already initialized"))
 	}
 
 	return nil
@@ -225,18 +303,20 @@ func (o *projectOptions) postScaffold() error {
 	}
 
 	ensured, err := o.scaffolder.EnsureDependencies()
-	if err != nil {
+	switch err.(type) {
+	case nil:
+	case *util.CommandError:
 		return err
+	default:
+		// the user could not be asked whether to fetch the dependencies
+		return withKind(kindMissingAnswer, err)
 	}
 
 	if !ensured {
+		o.output.warn("the dependencies are not fetched and make is not run")
 		return nil
 	}
 
 	fmt.Println("Running make...")
-	c := exec.Command("make") // #nosec
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	return c.Run()
+	return util.RunCommand(exec.Command("make")) // #nosec
 }
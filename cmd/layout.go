@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v1" // register the layouts
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+// layoutCommands are the commands only available in projects of a version,
+// in addition to the commands of all versions
+type layoutCommands struct {
+	// root commands are added to kubebuilder
+	root []func() *cobra.Command
+
+	// create commands are added to kubebuilder create. Outside of a project
+	// the create commands of the default version are added.
+	create []func() *cobra.Command
+
+	// alpha commands are added to kubebuilder alpha
+	alpha []func() *cobra.Command
+}
+
+func init() {
+	// the commands of the built-in layouts are implemented by kubebuilder
+	scaffold.RegisterLayoutCommands(project.Version1,
+		scaffold.LayoutCommand{New: newVendorUpdateCmd},
+		// version 2 projects use 'kubebuilder create webhook' instead of
+		// 'alpha webhook'
+		scaffold.LayoutCommand{Parent: "alpha", New: newWebhookCmd},
+		scaffold.LayoutCommand{Parent: "alpha", New: newMigrateCmd},
+	)
+	scaffold.RegisterLayoutCommands(project.Version2,
+		scaffold.LayoutCommand{Parent: "create", New: newWebhookV2Cmd},
+		scaffold.LayoutCommand{Parent: "alpha", New: newRescaffoldCmd},
+		scaffold.LayoutCommand{Parent: "alpha", New: newRenameCmd},
+		scaffold.LayoutCommand{Parent: "alpha", New: newRegenerateCmd},
+		scaffold.LayoutCommand{Parent: "alpha", New: newListCmd},
+		scaffold.LayoutCommand{Parent: "alpha", New: newUpdateHeadersCmd},
+	)
+}
+
+// newLayoutCommands returns the commands of the layout of the project
+// version, or an error for unsupported versions.
+func newLayoutCommands(version string) (layoutCommands, error) {
+	l, err := scaffold.GetLayout(version)
+	if err != nil {
+		return layoutCommands{}, err
+	}
+	var c layoutCommands
+	for _, cmd := range l.Commands {
+		switch cmd.Parent {
+		case "":
+			c.root = append(c.root, cmd.New)
+		case "create":
+			c.create = append(c.create, cmd.New)
+		case "alpha":
+			c.alpha = append(c.alpha, cmd.New)
+		default:
+			return layoutCommands{}, fmt.Errorf("unknown parent command %q of a command of the layout of project version %s",
+				cmd.Parent, version)
+		}
+	}
+	return c, nil
+}
+
+// projectLayout returns the commands of the layout of the project in the
+// current directory, they are empty outside of a project.
+func projectLayout() (layoutCommands, bool, error) {
+	foundProject, version, err := getProjectVersion()
+	if err != nil || !foundProject {
+		return layoutCommands{}, false, err
+	}
+	c, err := newLayoutCommands(version)
+	return c, true, err
+}
+
+// newCmds returns the commands created by fns.
+func newCmds(fns []func() *cobra.Command) []*cobra.Command {
+	cmds := make([]*cobra.Command, 0, len(fns))
+	for _, fn := range fns {
+		cmds = append(cmds, fn())
+	}
+	return cmds
+}
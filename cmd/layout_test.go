@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+func TestLayoutCommands(t *testing.T) {
+	for _, version := range scaffold.LayoutVersions() {
+		c, err := newLayoutCommands(version)
+		if err != nil {
+			t.Errorf("error %v", err)
+			continue
+		}
+		l, err := scaffold.GetLayout(version)
+		if err != nil {
+			t.Fatalf("error %v", err)
+		}
+		if n := len(c.root) + len(c.create) + len(c.alpha); n != len(l.Commands) {
+			t.Errorf("got %d commands for version %s and wanted %d", n, version, len(l.Commands))
+		}
+	}
+
+	if _, err := newLayoutCommands("3"); err == nil {
+		t.Errorf("got no error for an unsupported version")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+func newListCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the resources of the project and what was scaffolded for them",
+		Long: `List the resources recorded in PROJECT and what was scaffolded for them by 'create api' and
+'create webhook'.
+
+Each resource is listed with its plural, where its Go types are from, its scope, whether it has a
+controller and its webhooks. The types are from:
+
+  project   the API types were scaffolded in the project
+  core      the types of a Kubernetes API group from k8s.io/api
+  external  types from outside of the project
+
+Resources recorded before their options were recorded are listed as namespaced API types of the
+project without controller.
+`,
+		Example: `	# List the resources of the project
+	kubebuilder alpha list
+
+	# List the resources as JSON
+	kubebuilder alpha list --output json
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkProject(); err != nil {
+				return err
+			}
+
+			if output != "text" && output != "json" {
+				return withKind(kindInvalidFlags, fmt.Errorf("unknown output format %q, must be one of text,json", output))
+			}
+			p, err := scaffold.LoadProjectFile("PROJECT")
+			if err != nil {
+				return err
+			}
+			return printResources(os.Stdout, output, p.Resources)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "output format.  May be one of text,json")
+	return cmd
+}
+
+// printResources writes the resources to w in the given format.
+func printResources(w io.Writer, format string, resources []input.Resource) error {
+	if format == "json" {
+		if resources == nil {
+			resources = []input.Resource{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Resources []input.Resource `json:"resources"`
+		}{resources})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tVERSION\tKIND\tPLURAL\tTYPES\tSCOPE\tCONTROLLER\tWEBHOOKS")
+	for _, r := range resources {
+		types, scope := "-", "-"
+		switch {
+		case r.API != nil:
+			types, scope = "project", "Cluster"
+			if r.API.Namespaced {
+				scope = "Namespaced"
+			}
+		case r.Core:
+			types = "core"
+		case r.External:
+			types = "external"
+		}
+		controller := "no"
+		if r.Controller {
+			controller = "yes"
+		}
+		var webhooks []string
+		if r.Webhooks != nil {
+			for _, wh := range []struct {
+				name string
+				set  bool
+			}{
+				{"defaulting", r.Webhooks.Defaulting},
+				{"validation", r.Webhooks.Validation},
+				{"conversion", r.Webhooks.Conversion},
+			} {
+				if wh.set {
+					webhooks = append(webhooks, wh.name)
+				}
+			}
+		}
+		if len(webhooks) == 0 {
+			webhooks = []string{"-"}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Group, r.Version, r.Kind, r.Plural,
+			types, scope, controller, strings.Join(webhooks, ","))
+	}
+	return tw.Flush()
+}
@@ -19,7 +19,6 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 
@@ -104,36 +103,63 @@ func findCurrentRepo() (string, error) {
 }
 
 func main() {
+	os.Exit(exitCode(run()))
+}
+
+// run runs kubebuilder and returns the error of the command, which is only
+// reported by main.
+func run() error {
 	repoPath, err := findCurrentRepo()
 	if err != nil {
-		log.Fatal(fmt.Errorf("error finding current repository: %v", err))
+		return fmt.Errorf("error finding current repository: %v", err)
 	}
 
 	util.Repo = repoPath
 
+	rootCmd, err := newRootCmd()
+	if err != nil {
+		return err
+	}
+	return rootCmd.Execute()
+}
+
+// newRootCmd returns the kubebuilder command with the commands available in
+// the current directory.
+func newRootCmd() (*cobra.Command, error) {
+	layout, foundProject, err := projectLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	create := layout
+	if !foundProject {
+		// outside of a project the create commands of new projects are
+		// available
+		if create, err = newLayoutCommands(scaffold.DefaultVersion); err != nil {
+			return nil, err
+		}
+	}
+
 	rootCmd := defaultCommand()
 
 	rootCmd.AddCommand(
 		newInitProjectCmd(),
-		newCreateCmd(),
+		newCreateCmd(create),
+		newDeleteCmd(),
+		newConfigCmd(),
+		newAlphaCommand(layout),
 		version.NewVersionCmd(),
 	)
 
-	foundProject, version := getProjectVersion()
-	if foundProject && version == "1" {
-		rootCmd.AddCommand(
-			newAlphaCommand(),
-			newVendorUpdateCmd(),
-		)
-	}
+	rootCmd.AddCommand(newCmds(layout.root)...)
 
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
-	}
+	addPluginCmd(rootCmd, os.Args[1:])
+
+	return rootCmd, nil
 }
 
 func defaultCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "kubebuilder",
 		Short: "Development kit for building Kubernetes extensions and tools.",
 		Long: `
@@ -157,6 +183,20 @@ scaffold a Controller for an existing Resource, select "n" for Resource. To only
 the schema for a Resource without writing a Controller, select "n" for Controller.
 
 After the scaffold is written, api will run make on the project.
+
+The questions are answered by flags, e.g. --resource and --controller, or by their KUBEBUILDER_*
+environment variables, e.g. KUBEBUILDER_RESOURCE=false. With --non-interactive or
+KUBEBUILDER_NON_INTERACTIVE=true a question which is not answered is an error instead of a
+prompt, and with --yes or KUBEBUILDER_YES=true every such question is answered yes. The user is
+never prompted when stdin is not a terminal.
+
+An executable named kubebuilder-<name> on PATH is a plugin, run as kubebuilder <name> unless
+<name> is a built-in command.
+
+The exit code tells why a command failed: 1 for other errors, 2 for invalid flags, 3 when no
+PROJECT file is found, 4 for an invalid resource, 5 when a file already exists, 6 when a
+scaffold marker is missing, 7 for a question without an answer in non-interactive mode and 8
+when an external tool such as make or go fails.
 `,
 		Example: `
 	# Initialize your project
@@ -181,18 +221,33 @@ After the scaffold is written, api will run make on the project.
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 		},
+		// the errors are reported by main, without the usage unless the
+		// flags are invalid
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		err = withKind(kindInvalidFlags, fmt.Errorf("%v\nRun '%s --help' for usage.", err, c.CommandPath()))
+		if c.Flags().Lookup("output") == nil {
+			return err
+		}
+		return flagError(os.Args[1:], err)
+	})
+	bindInteractionFlags(cmd)
+	return cmd
 }
 
 // getProjectVersion tries to load PROJECT file and returns if the file exist
 // and the version string
-func getProjectVersion() (bool, string) {
+func getProjectVersion() (bool, string, error) {
 	if _, err := os.Stat("PROJECT"); os.IsNotExist(err) {
-		return false, ""
+		return false, "", nil
 	}
-	projectInfo, err := scaffold.LoadProjectFile("PROJECT")
+	// only the version is read, the commands report the other problems of
+	// the PROJECT file
+	version, err := scaffold.ProjectVersion("PROJECT")
 	if err != nil {
-		log.Fatalf("failed to read the PROJECT file: %v", err)
+		return false, "", fmt.Errorf("failed to read the PROJECT file: %v", err)
 	}
-	return true, projectInfo.Version
+	return true, version, nil
 }
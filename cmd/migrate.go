@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func newMigrateCmd() *cobra.Command {
+	o := migrateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the project to the layout of the next project version",
+		Long: `Migrate a version 1 project to the v2 layout of project version 2.
+
+The types of pkg/apis/<group>/<version> move to api/<version>, or api/<group>/<version> when
+the project has more than one group, and the controllers of pkg/controller/<kind> move to
+controllers/. The Add function of a controller becomes the SetupWithManager method of its
+reconciler, Reconcile<Kind> is renamed to <Kind>Reconciler, and the imports of the moved
+packages are updated in every Go file of the project.
+
+The files scaffolded by version 1 are replaced by the ones of the v2 layout: main.go is
+generated with its scaffold markers and wires the types and the controllers, and the
+constraints and overrides of Gopkg.toml become the requirements and replace directives of
+go.mod. PROJECT records the resources found in the project.
+
+The code which cannot be converted is reported and kept with a .v1 suffix, so the project
+builds without it, for you to port: the files of the v1 scaffolding you changed and the other
+files left in the directories of the v1 layout, such as the webhooks. The files of the v1
+scaffolding which are unchanged, including the scaffolded tests, are removed.
+
+Generated code and manifests are not generated again, run go mod tidy and make afterwards.
+`,
+		Example: `	# Preview the files the migration touches
+	kubebuilder alpha migrate --dry-run --dry-run-format files
+
+	# Migrate the project, then fetch its modules and generate the code
+	kubebuilder alpha migrate
+	go mod tidy
+	make
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	o.dryRun.bindFlags(cmd.Flags())
+
+	return cmd
+}
+
+// migrateOptions represents commandline options for migrating a project.
+type migrateOptions struct {
+	migrate scaffold.Migrate
+
+	dryRun dryRunOptions
+}
+
+func (o *migrateOptions) run() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+
+	overlay, err := filesystem.Stage(o.migrate.Migrate)
+	if err != nil {
+		return err
+	}
+	if o.dryRun.dryRun && o.dryRun.format == "diff" {
+		err = overlay.Diff(os.Stdout)
+	} else {
+		err = printMoves(os.Stdout, overlay, o.migrate.Moved)
+	}
+	if err != nil {
+		return err
+	}
+	if err := o.report(os.Stdout); err != nil {
+		return err
+	}
+	if o.dryRun.dryRun {
+		return nil
+	}
+
+	if err := overlay.Commit(); err != nil {
+		return err
+	}
+	fmt.Println("Run go mod tidy to fetch the modules, and make to generate the code and manifests.")
+	return nil
+}
+
+// report writes the code the migration could not convert to w.
+func (o *migrateOptions) report(w io.Writer) error {
+	if len(o.migrate.Unconverted) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "\nThe code below is not converted:"); err != nil {
+		return err
+	}
+	for _, u := range o.migrate.Unconverted {
+		path := u.Path
+		if u.KeptAs != "" {
+			path = fmt.Sprintf("%s (kept as %s)", u.Path, u.KeptAs)
+		}
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", path, u.Reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+// result is the outcome of a command, written to stdout with --output json
+type result struct {
+	// Created are the paths of the files created
+	Created []string `json:"created"`
+
+	// Modified are the files modified
+	Modified []modifiedFile `json:"modified"`
+
+	// Deleted are the paths of the files removed
+	Deleted []string `json:"deleted"`
+
+	// DryRun is set if the files were not written because of --dry-run
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Commands are the command lines run, e.g. make
+	Commands []string `json:"commands"`
+
+	// Warnings are the problems which did not stop the command
+	Warnings []string `json:"warnings"`
+
+	// Error is the error which stopped the command, nil on success
+	Error *resultError `json:"error,omitempty"`
+}
+
+// modifiedFile is a file modified by a command
+type modifiedFile struct {
+	Path string `json:"path"`
+
+	// Markers are the names of the scaffold markers the code was inserted
+	// at, e.g. scheme for // +kubebuilder:scaffold:scheme
+	Markers []string `json:"markers"`
+}
+
+// resultError is the error of a result
+type resultError struct {
+	// Code is the code of the kind of the error, see errorKind
+	Code string `json:"code"`
+
+	Message string `json:"message"`
+}
+
+// outputOptions represents the commandline option for the output format of
+// a command, and the result of the command written in the json format.
+type outputOptions struct {
+	// format is the output format, one of text,json
+	format string
+
+	result result
+}
+
+func (o *outputOptions) bindFlags(f *flag.FlagSet) {
+	f.StringVarP(&o.format, "output", "o", "text",
+		"output format.  May be one of text,json")
+}
+
+func (o *outputOptions) validate() error {
+	switch o.format {
+	case "text", "json":
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of text,json", o.format)
+	}
+}
+
+// run runs the command fn. With the json format, everything written to
+// stdout by fn and the commands it runs goes to stderr instead, and the
+// result is written to stdout.
+func (o *outputOptions) run(fn func() error) error {
+	if err := o.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+	if o.format == "text" {
+		return fn()
+	}
+
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	err := fn()
+	os.Stdout = stdout
+
+	o.result.Commands = append([]string{}, util.Commands...)
+	if err != nil {
+		o.result.Error = &resultError{Code: kindOf(err).code, Message: err.Error()}
+	}
+	if werr := o.write(stdout); werr != nil {
+		return werr
+	}
+	if err != nil {
+		// the error is reported in the result
+		return &reportedError{err: err}
+	}
+	return nil
+}
+
+// flagError returns err, the error parsing the flags of a command with an
+// output format. The flags after the error are not parsed, so the format is
+// looked up in the command line args: with the json format the error is
+// written to stdout as the result of the command.
+func flagError(args []string, err error) error {
+	o := &outputOptions{}
+	fs := flag.NewFlagSet("output", flag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(ioutil.Discard)
+	o.bindFlags(fs)
+	if fs.Parse(args) != nil || o.format != "json" {
+		return err
+	}
+	return o.run(func() error { return err })
+}
+
+// write writes the result to w in the json format.
+func (o *outputOptions) write(w io.Writer) error {
+	r := o.result
+	for _, list := range []*[]string{&r.Created, &r.Deleted, &r.Commands, &r.Warnings} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	if r.Modified == nil {
+		r.Modified = []modifiedFile{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// recordChanges adds the changes of the scaffolding to the result.
+func (o *outputOptions) recordChanges(changes []filesystem.Change, dryRun bool) {
+	o.result.DryRun = dryRun
+	for _, c := range changes {
+		switch {
+		case c.Created():
+			o.result.Created = append(o.result.Created, c.Path)
+		case c.Removed():
+			o.result.Deleted = append(o.result.Deleted, c.Path)
+		default:
+			markers := scaffold.MarkersUsed(c)
+			o.result.Modified = append(o.result.Modified, modifiedFile{Path: c.Path, Markers: markers})
+		}
+	}
+}
+
+// warn reports a problem which does not stop the command, it is printed to
+// stderr and added to the warnings of the result.
+func (o *outputOptions) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	o.result.Warnings = append(o.result.Warnings, msg)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func TestOutputResult(t *testing.T) {
+	o := outputOptions{format: "json"}
+	o.recordChanges([]filesystem.Change{
+		{Path: "api/v1/frigate_types.go", New: []byte("package v1\n")},
+		{
+			Path: "main.go",
+			Old:  []byte("func init() {\n\t// +kubebuilder:scaffold:scheme\n}\n"),
+			New:  []byte("func init() {\n\t_ = shipv1.AddToScheme(scheme)\n\t// +kubebuilder:scaffold:scheme\n}\n"),
+		},
+	}, false)
+	o.warn("neither the resource nor the controller is created")
+	o.result.Error = &resultError{Code: kindExternalToolFailed.code, Message: "error running make: exit status 2"}
+
+	out := &bytes.Buffer{}
+	if err := o.write(out); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	expected := `{
+  "created": [
+    "api/v1/frigate_types.go"
+  ],
+  "modified": [
+    {
+      "path": "main.go",
+      "markers": [
+        "scheme"
+      ]
+    }
+  ],
+  "deleted": [],
+  "commands": [],
+  "warnings": [
+    "neither the resource nor the controller is created"
+  ],
+  "error": {
+    "code": "external-tool-failed",
+    "message": "error running make: exit status 2"
+  }
+}
+`
+	if out.String() != expected {
+		t.Errorf("got\n%s\nexpected\n%s", out.String(), expected)
+	}
+
+	if err := (&outputOptions{format: "yaml"}).validate(); err == nil {
+		t.Errorf("expected an error for the yaml format")
+	}
+}
+
+func TestFlagErrorOutput(t *testing.T) {
+	f, err := ioutil.TempFile("", "kubebuilder-stdout-")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	stdout := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+	// no command is run before the flags are parsed
+	commands := util.Commands
+	util.Commands = nil
+	defer func() { util.Commands = commands }()
+
+	flagErr := withKind(kindInvalidFlags, fmt.Errorf("unknown flag: --bogus"))
+	if err := flagError([]string{"create", "api", "--bogus"}, flagErr); err != flagErr {
+		t.Errorf("got %v, expected the flag error with the text format", err)
+	}
+	err = flagError([]string{"create", "api", "--bogus", "-o", "json"}, flagErr)
+	if _, reported := err.(*reportedError); !reported || kindOf(err) != kindInvalidFlags {
+		t.Errorf("got %v, expected the flag error to be reported", err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	expected := `{
+  "created": [],
+  "modified": [],
+  "deleted": [],
+  "commands": [],
+  "warnings": [],
+  "error": {
+    "code": "invalid-flags",
+    "message": "unknown flag: --bogus"
+  }
+}
+`
+	if string(b) != expected {
+		t.Errorf("got\n%s\nexpected\n%s", b, expected)
+	}
+}
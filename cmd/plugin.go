@@ -0,0 +1,158 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/plugin"
+)
+
+// addPluginCmd adds the command of the plugin named by the first argument of
+// args to root, unless it names a built-in command. The plugins are only
+// looked up on PATH for the commands which are not built-in.
+func addPluginCmd(root *cobra.Command, args []string) {
+	if cmd, _, err := root.Find(args); err == nil && cmd != root {
+		return
+	}
+	var name string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			name = arg
+			break
+		}
+	}
+	if name == "" {
+		return
+	}
+	path, err := plugin.Lookup(name)
+	if err != nil {
+		return
+	}
+	root.AddCommand(newPluginCmd(name, path))
+}
+
+func newPluginCmd(name, path string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: fmt.Sprintf("Run the plugin %s", path),
+		Long: fmt.Sprintf(`Run the external plugin %s.
+
+The arguments and flags are passed to the plugin, which returns the files and edits for
+kubebuilder to apply. Flags are given as --name=value or --name value, flags without a
+value are passed as "true".
+
+The --dry-run and --dry-run-format flags are not passed to the plugin, they preview the
+changes of the plugin instead of writing them.
+`, path),
+		Example: fmt.Sprintf(`	# Preview the changes of the plugin
+	kubebuilder %s --dry-run
+`, name),
+		// the flags are defined by the plugin
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, flags := parsePluginArgs(args)
+			if _, ok := flags["help"]; ok {
+				return cmd.Help()
+			}
+
+			o := dryRunOptions{format: "diff"}
+			if v, ok := flags["dry-run"]; ok {
+				o.dryRun = v == "true"
+				delete(flags, "dry-run")
+			}
+			if v, ok := flags["dry-run-format"]; ok {
+				o.format = v
+				delete(flags, "dry-run-format")
+			}
+			if err := o.validate(); err != nil {
+				return withKind(kindInvalidFlags, err)
+			}
+
+			req, err := scaffold.NewPluginRequest(args, flags)
+			if err != nil {
+				return err
+			}
+			resp, err := plugin.Call(path, req, os.Stderr)
+			if err != nil {
+				return withKind(kindExternalToolFailed, err)
+			}
+
+			var conflicts []string
+			_, err = o.run(func() error {
+				var err error
+				conflicts, err = scaffold.ApplyPlugin(resp)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, path := range conflicts {
+				fmt.Printf("CONFLICT: merge conflict in %s\n", path)
+			}
+			if resp.Message != "" {
+				fmt.Println(resp.Message)
+			}
+			if len(conflicts) > 0 && !o.dryRun {
+				return fmt.Errorf("the plugin finished with conflicts, resolve them and remove the conflict markers")
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// parsePluginArgs splits the command line of a plugin into its positional
+// arguments and its flags. A flag without a value followed by an argument
+// takes it as value, so boolean flags preceding arguments need =true.
+func parsePluginArgs(cmdline []string) ([]string, map[string]string) {
+	var args []string
+	flags := map[string]string{}
+	for i := 0; i < len(cmdline); i++ {
+		a := cmdline[i]
+		if a == "--" {
+			args = append(args, cmdline[i+1:]...)
+			break
+		}
+		if a == "-h" {
+			a = "--help"
+		}
+		if !strings.HasPrefix(a, "--") {
+			args = append(args, a)
+			continue
+		}
+
+		name := strings.TrimPrefix(a, "--")
+		if j := strings.Index(name, "="); j >= 0 {
+			flags[name[:j]] = name[j+1:]
+			continue
+		}
+		if i+1 < len(cmdline) && !strings.HasPrefix(cmdline[i+1], "-") {
+			flags[name] = cmdline[i+1]
+			i++
+			continue
+		}
+		flags[name] = "true"
+	}
+	return args, flags
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func newRegenerateCmd() *cobra.Command {
+	rg := &scaffold.Regenerate{}
+
+	cmd := &cobra.Command{
+		Use:   "regenerate",
+		Short: "Scaffold the project again from PROJECT into a new directory",
+		Long: `Scaffold the project again from its PROJECT file into a new directory, with the templates of
+this kubebuilder release.
+
+The project is initialized with the domain, repo, layout and boilerplate of the project, and
+every resource recorded in PROJECT is created again with the options it was created with: its
+API types, controller and webhooks. Diffing the project against the new directory shows the
+changes made to the scaffolding and the changes of the templates, which is the simplest way to
+upgrade a project to a new kubebuilder release.
+
+Resources recorded before their options were recorded are created with namespaced API types
+only.
+`,
+		Example: `	# Scaffold the project into ../regenerated and compare it
+	kubebuilder alpha regenerate --output ../regenerated
+	diff -r . ../regenerated
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkProject(); err != nil {
+				return err
+			}
+
+			if rg.Output == "" {
+				return withKind(kindInvalidFlags, fmt.Errorf("--output must be set"))
+			}
+			if err := filesystem.Transaction(rg.Regenerate); err != nil {
+				return err
+			}
+			fmt.Printf("Project scaffolded again into %s\n", rg.Output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&rg.Output, "output", "", "directory to scaffold the project into, must be empty")
+	return cmd
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+func newRenameCmd() *cobra.Command {
+	o := renameOptions{
+		rename: scaffold.Rename{From: &resource.Resource{}, To: &resource.Resource{}},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "rename",
+		Short: "Rename the kind, group or version of a Kubernetes API",
+		Long: `Rename the kind, group or version of a Kubernetes API in the Go code, the file names
+and the manifests of the project, and in PROJECT.
+
+The Go types of the kind and their uses, the reconciler and its wiring in main.go are renamed
+in the Go syntax tree. The file names, the RBAC and webhook markers, the webhook paths and the
+samples, patches and CRD entries of config/ are renamed following the naming of the scaffolding.
+Every file touched is reported.
+
+Generated code and manifests are not generated again, run make afterwards.
+`,
+		Example: `	# Rename the kind Frigate of Group: ship, Version: v1beta1 to Corvette
+	kubebuilder alpha rename --group ship --version v1beta1 --kind Frigate --new-kind Corvette
+
+	# Preview moving it to the version v1
+	kubebuilder alpha rename --group ship --version v1beta1 --kind Frigate --new-version v1 --dry-run
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	cmd.Flags().StringVar(&o.rename.From.Kind, "kind", "", "resource Kind")
+	cmd.Flags().StringVar(&o.rename.From.Group, "group", "", "resource Group")
+	cmd.Flags().StringVar(&o.rename.From.Version, "version", "", "resource Version")
+	cmd.Flags().StringVar(&o.rename.To.Kind, "new-kind", "", "new resource Kind, the Kind if empty")
+	cmd.Flags().StringVar(&o.rename.To.Group, "new-group", "", "new resource Group, the Group if empty")
+	cmd.Flags().StringVar(&o.rename.To.Version, "new-version", "", "new resource Version, the Version if empty")
+	o.dryRun.bindFlags(cmd.Flags())
+
+	return cmd
+}
+
+// renameOptions represents commandline options for renaming an API.
+type renameOptions struct {
+	rename scaffold.Rename
+
+	dryRun dryRunOptions
+}
+
+func (o *renameOptions) run() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+	if err := o.rename.Validate(); err != nil {
+		return withKind(kindInvalidResource, err)
+	}
+
+	overlay, err := filesystem.Stage(o.rename.Rename)
+	if err != nil {
+		return err
+	}
+	if o.dryRun.dryRun && o.dryRun.format == "diff" {
+		return overlay.Diff(os.Stdout)
+	}
+	if err := printMoves(os.Stdout, overlay, o.rename.Moved); err != nil {
+		return err
+	}
+	if o.dryRun.dryRun {
+		return nil
+	}
+
+	if err := overlay.Commit(); err != nil {
+		return err
+	}
+	fmt.Println("Run make to generate the deepcopy functions and manifests of the renamed API.")
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+func newRescaffoldCmd() *cobra.Command {
+	o := dryRunOptions{}
+	var image string
+
+	cmd := &cobra.Command{
+		Use:   "rescaffold",
+		Short: "Merge the latest templates into the scaffolded project files",
+		Long: `Render the Makefile, Dockerfile, config/manager/manager.yaml and main.go again and merge the
+changes of their templates into the existing files, keeping the changes made to them.
+
+The content last rendered for each file is kept in .kubebuilder/base as base of the three-way
+merge. Where the changes made to a file and the changes of its template disagree, both are
+written between conflict markers, which must be resolved by hand. Files scaffolded before
+their base was kept are merged without a base, so every difference is reported as a conflict.
+
+The image of the controller manager defaults to the one given to init, which is recorded in
+PROJECT unless it is controller:latest.
+`,
+		Example: `	# Preview the changes of the latest templates
+	kubebuilder alpha rescaffold --dry-run
+
+	# Merge the latest templates into the project files
+	kubebuilder alpha rescaffold
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.validate(); err != nil {
+				return withKind(kindInvalidFlags, err)
+			}
+
+			var conflicts []string
+			_, err := o.run(func() error {
+				var err error
+				conflicts, err = (&scaffold.V2Project{Image: image}).Rescaffold()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, path := range conflicts {
+				fmt.Printf("CONFLICT: merge conflict in %s\n", path)
+			}
+			if len(conflicts) > 0 && !o.dryRun {
+				return fmt.Errorf("rescaffold finished with conflicts, resolve them and remove the conflict markers")
+			}
+			return nil
+		},
+	}
+	o.bindFlags(cmd.Flags())
+	cmd.Flags().StringVar(&image, "image", "",
+		"image of the controller manager, defaults to the image given to init")
+	return cmd
+}
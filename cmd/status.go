@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+)
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List the scaffolded files and whether they were changed",
+		Long: `List the files written by kubebuilder and whether they were changed since.
+
+Kubebuilder records the files it writes in .kubebuilder/manifest.yaml, with the template they
+were rendered from, the kubebuilder version and the hash of their content. Each file is listed as:
+
+  pristine  the file has not been changed since kubebuilder wrote it
+  modified  the file has been changed
+  deleted   the file has been removed
+
+Files scaffolded before the manifest was introduced are not listed.
+`,
+		Example: `	# List the scaffolded files
+	kubebuilder alpha status
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printStatus(os.Stdout)
+		},
+	}
+}
+
+// printStatus writes the state of the scaffolded files to w.
+func printStatus(w io.Writer) error {
+	m, err := manifest.Load()
+	if err != nil {
+		return err
+	}
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATE\tPATH\tTEMPLATE\tVERSION")
+	for _, s := range statuses {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.State, s.Path, s.Template, s.Version)
+	}
+	return tw.Flush()
+}
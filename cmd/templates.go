@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/templates"
+)
+
+func newDumpTemplatesCmd() *cobra.Command {
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:   "dump-templates",
+		Short: "Write the built-in scaffolding templates to a directory",
+		Long: `Write the built-in templates of all scaffolded files to a directory, as a starting point for
+customizing them.
+
+Each template is written to <output>/<package>/<Type>.tmpl, e.g. v2/Controller.tmpl for the
+controller of version 2 projects. Templates are rendered with the same data as the built-in ones,
+e.g. .Resource, .Domain, .Repo and .Boilerplate.
+
+The templates composed from the flags are written by part, e.g. the webhook of version 2
+projects as v2/webhook/Webhook/base.tmpl, defaulting.tmpl and validating.tmpl: the defaulting
+and validating parts are only used with --defaulting and --programmatic-validation.
+
+To use the templates, pass the directory to 'kubebuilder init --template-dir' or set it as
+'templates' in the PROJECT file. Remove the templates you do not customize, files without a
+template in the directory are scaffolded with the built-in one. Existing templates are not
+overwritten.
+`,
+		Example: `	# Write the built-in templates to ./templates
+	kubebuilder alpha dump-templates --output templates
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dumpTemplates(outputDir)
+		},
+	}
+	cmd.Flags().StringVar(&outputDir, "output", "templates", "directory to write the templates to")
+	return cmd
+}
+
+// dumpTemplates writes the built-in templates to dir, skipping the ones that
+// already exist.
+func dumpTemplates(dir string) error {
+	builtin, err := templates.Builtin()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(builtin))
+	for key := range builtin {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return filesystem.Transaction(func() error {
+		for _, key := range keys {
+			path := scaffold.TemplatePath(dir, key)
+			exists, err := afero.Exists(filesystem.Fs, path)
+			if err != nil {
+				return err
+			}
+			if exists {
+				fmt.Printf("skipping %s, already exists\n", path)
+				continue
+			}
+
+			fmt.Println(path)
+			if err := (&scaffold.FileWriter{Fs: filesystem.Fs}).WriteFile(path, []byte(builtin[key])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func newUpdateHeadersCmd() *cobra.Command {
+	o := updateHeadersOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "update-headers",
+		Short: "Replace the license headers of the project files by the boilerplate",
+		Long: `Replace the license headers of the project files by the boilerplate, e.g. after changing
+hack/boilerplate.go.txt for a new owner or license.
+
+The header of a Go file is its first comment when it is followed by a blank line. The headers
+of main.go and of the Go files under api/ and controllers/ are replaced, and added to the
+files without header. The generated files, zz_generated.* and the files marked
+"Code generated ... DO NOT EDIT.", are left to their generator, run make afterwards.
+
+The header of a YAML file under config/ is its first comment lines when they are followed by a
+blank line and mention a copyright or a license. It is replaced by the boilerplate as YAML
+comments, the YAML files without header are left unchanged.
+
+With --check no file is written: the files whose header differs from the boilerplate are
+listed, and the command fails if there are any.
+`,
+		Example: `	# Replace the headers by hack/boilerplate.go.txt
+	kubebuilder alpha update-headers
+
+	# Fail if a header differs from the boilerplate, e.g. in CI
+	kubebuilder alpha update-headers --check
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	cmd.Flags().StringVar(&o.headers.BoilerplatePath, "path", filepath.Join("hack", "boilerplate.go.txt"),
+		"path of the boilerplate")
+	markConfigFlag(cmd.Flags(), "path", "boilerplatePath")
+	cmd.Flags().BoolVar(&o.check, "check", false,
+		"if set, list the files whose header differs from the boilerplate and fail if there are any, "+
+			"without writing them")
+	o.dryRun.bindFlags(cmd.Flags())
+
+	return cmd
+}
+
+// updateHeadersOptions represents commandline options for updating the
+// license headers.
+type updateHeadersOptions struct {
+	headers scaffold.UpdateHeaders
+
+	// check lists the outdated headers instead of updating them
+	check bool
+
+	dryRun dryRunOptions
+}
+
+func (o *updateHeadersOptions) run() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+
+	if o.check {
+		// the update is staged to find the outdated headers, and discarded
+		if _, err := filesystem.Stage(o.headers.Update); err != nil {
+			return err
+		}
+		for _, path := range o.headers.Updated {
+			fmt.Printf("%s: the header differs from %s\n", path, o.headers.BoilerplatePath)
+		}
+		if n := len(o.headers.Updated); n > 0 {
+			return fmt.Errorf("%d files have an outdated header, run 'kubebuilder alpha update-headers' to update them", n)
+		}
+		return nil
+	}
+
+	if _, err := o.dryRun.run(o.headers.Update); err != nil {
+		return err
+	}
+	if o.dryRun.dryRun {
+		return nil
+	}
+	for _, path := range o.headers.Updated {
+		fmt.Printf("Updated the header of %s\n", path)
+	}
+	return nil
+}
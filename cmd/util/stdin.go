@@ -19,20 +19,69 @@ package util
 import (
 	"bufio"
 	"fmt"
-	"log"
+	"os"
 	"strings"
 )
 
+var (
+	// AssumeYes answers yes to the questions which are not answered by flags
+	AssumeYes bool
+
+	// NonInteractive makes the questions which are not answered by flags
+	// errors instead of prompts
+	NonInteractive bool
+
+	// stdin is shared by the questions, so an answer buffered by a reader is
+	// not lost
+	stdin *bufio.Reader
+)
+
+// Ask prompts the user with question on stdin and returns true if the answer
+// is yes. flag is the flag answering the question. The user is not prompted
+// with AssumeYes, and it is an error naming the flag and its environment
+// variable in non-interactive mode or if stdin is not a terminal.
+func Ask(question, flag string) (bool, error) {
+	switch {
+	case AssumeYes:
+		return true, nil
+	case NonInteractive:
+		return false, fmt.Errorf("%s: no answer in non-interactive mode, set --%s or %s",
+			question, flag, EnvName(flag))
+	case !IsTerminal(os.Stdin):
+		return false, fmt.Errorf("%s: stdin is not a terminal to prompt, set --%s or %s",
+			question, flag, EnvName(flag))
+	}
+	if stdin == nil {
+		stdin = bufio.NewReader(os.Stdin)
+	}
+	fmt.Printf("%s [y/n]\n", question)
+	return Yesno(stdin)
+}
+
+// IsTerminal returns true if f is a terminal.
+func IsTerminal(f *os.File) bool {
+	return isTerminal(f)
+}
+
+// EnvName returns the environment variable of a flag, e.g.
+// KUBEBUILDER_FETCH_DEPS for --fetch-deps.
+func EnvName(flag string) string {
+	return "KUBEBUILDER_" + strings.ToUpper(strings.Replace(flag, "-", "_", -1))
+}
+
 // Yesno reads from stdin looking for one of "y", "yes", "n", "no" and returns
 // true for "y" and false for "n"
-func Yesno(reader *bufio.Reader) bool {
+func Yesno(reader *bufio.Reader) (bool, error) {
 	for {
-		text := readstdin(reader)
+		text, err := readstdin(reader)
+		if err != nil {
+			return false, err
+		}
 		switch text {
 		case "y", "yes":
-			return true
+			return true, nil
 		case "n", "no":
-			return false
+			return false, nil
 		default:
 			fmt.Printf("invalid input %q, should be [y/n]", text)
 		}
@@ -40,11 +89,10 @@ func Yesno(reader *bufio.Reader) bool {
 }
 
 // Readstdin reads a line from stdin trimming spaces, and returns the value.
-// log.Fatal's if there is an error.
-func readstdin(reader *bufio.Reader) string {
+func readstdin(reader *bufio.Reader) (string, error) {
 	text, err := reader.ReadString('\n')
 	if err != nil {
-		log.Fatalf("Error when reading input: %v", err)
+		return "", fmt.Errorf("Error when reading input: %v", err)
 	}
-	return strings.TrimSpace(text)
+	return strings.TrimSpace(text), nil
 }
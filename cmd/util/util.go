@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"go/format"
 	"io/ioutil"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -36,63 +35,68 @@ var Domain string
 var Repo string
 
 // writeIfNotFound returns true if the file was created and false if it already exists
-func WriteIfNotFound(path, templateName, templateValue string, data interface{}) bool {
+func WriteIfNotFound(path, templateName, templateValue string, data interface{}) (bool, error) {
 	// Make sure the directory exists
-	os.MkdirAll(filepath.Dir(path), 0700)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return false, err
+	}
 
 	// Don't create the doc.go if it exists
 	if _, err := os.Stat(path); err == nil {
-		return false
+		return false, nil
 	} else if !os.IsNotExist(err) {
-		log.Fatalf("Could not stat %s: %v", path, err)
+		return false, fmt.Errorf("Could not stat %s: %v", path, err)
 	}
 
-	return Write(path, templateName, templateValue, data)
+	if err := Write(path, templateName, templateValue, data); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func Write(path, templateName, templateValue string, data interface{}) bool {
-	t := template.Must(template.New(templateName).Funcs(
+func Write(path, templateName, templateValue string, data interface{}) error {
+	t, err := template.New(templateName).Funcs(
 		template.FuncMap{
 			"title":  strings.Title,
 			"lower":  strings.ToLower,
 			"plural": flect.Pluralize,
 		},
-	).Parse(templateValue))
+	).Parse(templateValue)
+	if err != nil {
+		return fmt.Errorf("Failed to parse template %s: %v", templateName, err)
+	}
 
 	var tmp bytes.Buffer
-	err := t.Execute(&tmp, data)
+	err = t.Execute(&tmp, data)
 	if err != nil {
-		log.Fatalf("Failed to render template %s: %v", templateName, err)
+		return fmt.Errorf("Failed to render template %s: %v", templateName, err)
 	}
 
 	content := tmp.Bytes()
 	if filepath.Ext(path) == ".go" {
 		content, err = format.Source(content)
 		if err != nil {
-			log.Fatalf("Failed to format template %s: %v", templateName, err)
+			return fmt.Errorf("Failed to format template %s: %v", templateName, err)
 		}
 	}
 
-	WriteString(path, string(content))
-
-	return true
+	return WriteString(path, string(content))
 }
 
-func WriteString(path, value string) {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		create(path)
-	}
-
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+func WriteString(path, value string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
-		log.Fatalf("Failed to create %s: %v", path, err)
+		return fmt.Errorf("Failed to create %s: %v", path, err)
 	}
-	defer f.Close()
 
-	_, err = f.WriteString(value)
-	if err != nil {
-		log.Fatalf("Failed to write %s: %v", path, err)
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		return fmt.Errorf("Failed to write %s: %v", path, err)
 	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Failed to write %s: %v", path, err)
+	}
+	return nil
 }
 
 // GetCopyright will return the contents of the copyright file if it exists.
@@ -108,38 +112,51 @@ func GetCopyright(file string) string {
 	return string(cr)
 }
 
-func GetDomain() string {
+func GetDomain() (string, error) {
 	b, err := ioutil.ReadFile(filepath.Join("pkg", "apis", "doc.go"))
 	if err != nil {
-		log.Fatalf("Could not find pkg/apis/doc.go.  First run `kubebuilder init --domain <domain>`.")
+		return "", fmt.Errorf("Could not find pkg/apis/doc.go.  First run `kubebuilder init --domain <domain>`.")
 	}
 	r := regexp.MustCompile("\\+domain=(.*)")
 	l := r.FindSubmatch(b)
 	if len(l) < 2 {
-		log.Fatalf("pkg/apis/doc.go does not contain the domain (// +domain=.*)")
+		return "", fmt.Errorf("pkg/apis/doc.go does not contain the domain (// +domain=.*)")
 	}
 	Domain = string(l[1])
-	return Domain
+	return Domain, nil
 }
 
-func create(path string) {
-	f, err := os.Create(path)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	defer f.Close()
+func DoCmd(cmd string, args ...string) error {
+	return RunCommand(exec.Command(cmd, args...))
+}
+
+// Commands are the command lines run by RunCommand, in order
+var Commands []string
+
+// CommandError is the error of a command run by RunCommand which failed
+type CommandError struct {
+	// Command is the command line
+	Command string
+
+	Err error
 }
 
-func DoCmd(cmd string, args ...string) {
-	c := exec.Command(cmd, args...)
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("error running %s: %v", e.Command, e.Err)
+}
+
+// RunCommand prints the command line of c and runs it with the output of
+// kubebuilder. The command line is recorded in Commands.
+func RunCommand(c *exec.Cmd) error {
 	c.Stderr = os.Stderr
 	c.Stdout = os.Stdout
-	log.Printf("%s\n", strings.Join(c.Args, " "))
-	err := c.Run()
-	if err != nil {
-		log.Fatalf("command failed %v", err)
+	line := strings.Join(c.Args, " ")
+	fmt.Println(line)
+	Commands = append(Commands, line)
+	if err := c.Run(); err != nil {
+		return &CommandError{Command: line, Err: err}
 	}
+	return nil
 }
 
 func IsNewVersion() bool {
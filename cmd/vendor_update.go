@@ -17,29 +17,105 @@ limitations under the License.
 package main
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
 )
 
 func newVendorUpdateCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update vendor dependencies",
 		Long:  `Update vendor dependencies`,
 		Example: `Update the vendor dependencies:
 kubebuilder update vendor
+
+Write a go.mod with the dependencies locked by dep:
+kubebuilder update gomod
 `,
-		Run: func(cmd *cobra.Command, args []string) {
-			dieIfNoProject()
-			err := (&scaffold.Scaffold{}).Execute(input.Options{},
-				&project.GopkgToml{})
-			if err != nil {
-				log.Fatalf("error updating vendor dependecies %v", err)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateVendor()
+		},
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "vendor",
+			Short: "Update the managed stanzas of Gopkg.toml",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return updateVendor()
+			},
+		},
+		newGoModUpdateCmd(),
+	)
+	return cmd
+}
+
+func updateVendor() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+	err := filesystem.Transaction(func() error {
+		return (&scaffold.Scaffold{}).Execute(input.Options{},
+			&project.GopkgToml{})
+	})
+	if err != nil {
+		return fmt.Errorf("error updating vendor dependecies %v", err)
+	}
+	return nil
+}
+
+func newGoModUpdateCmd() *cobra.Command {
+	g := &scaffold.GoModFromDep{}
+	dryRun := dryRunOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "gomod",
+		Short: "Write a go.mod with the dependencies locked by dep",
+		Long: `Write the go.mod of the project from Gopkg.lock, so the project can build with Go modules
+instead of dep.
+
+Every project locked in Gopkg.lock is required at its locked version. The sources of the
+locked projects, and the versions of the overrides and the exact constraints (version = "=...")
+of Gopkg.toml which differ from the locked ones, the managed stanzas included, become replace
+directives so the same versions are built. Only the files of the project are read, no network
+access is needed.
+
+A project locked on a revision only, e.g. on a branch, is required at the version of its
+constraint or override in Gopkg.toml. Its pseudo-version cannot be known without fetching the
+revision, so go.mod is not written if such a project has no version in Gopkg.toml, e.g.:
+
+  [[override]]
+    name = "golang.org/x/net"
+    version = "=v0.0.0-20190311183353-d8887717615a"
+
+Gopkg.toml, Gopkg.lock and the vendor directory are kept. Run go mod tidy afterwards to drop
+the modules the project does not use.
+`,
+		Example: `	# Preview the go.mod
+	kubebuilder update gomod --dry-run
+
+	# Write the go.mod and drop the unused modules
+	kubebuilder update gomod
+	go mod tidy
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkProject(); err != nil {
+				return err
+			}
+
+			if err := dryRun.validate(); err != nil {
+				return withKind(kindInvalidFlags, err)
+			}
+			if _, err := dryRun.run(g.Convert); err != nil {
+				return withKind(kindOf(err), fmt.Errorf("error writing go.mod: %v", err))
 			}
+			return nil
 		},
 	}
+	dryRun.bindFlags(cmd.Flags())
+	return cmd
 }
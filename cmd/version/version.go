@@ -17,7 +17,10 @@ limitations under the License.
 package version
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -57,15 +60,34 @@ func (v Version) Print() {
 }
 
 func NewVersionCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "version",
-		Short:   "Print the kubebuilder version",
-		Long:    `Print the kubebuilder version`,
-		Example: `kubebuilder version`,
-		Run:     runVersion,
+	var output string
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the kubebuilder version",
+		Long:  `Print the kubebuilder version`,
+		Example: `kubebuilder version
+
+# Print the version as JSON
+kubebuilder version --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVersion(os.Stdout, output)
+		},
 	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"output format.  May be one of text,json")
+	return cmd
 }
 
-func runVersion(cmd *cobra.Command, args []string) {
-	GetVersion().Print()
+func runVersion(w io.Writer, output string) error {
+	switch output {
+	case "text":
+		GetVersion().Print()
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(GetVersion())
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of text,json", output)
+	}
 }
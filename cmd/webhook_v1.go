@@ -18,16 +18,14 @@ package main
 
 import (
 	"fmt"
-	"log"
-	"os"
 	"os/exec"
-	"strings"
 
-	"github.com/gobuffalo/flect"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
+	"sigs.k8s.io/kubebuilder/cmd/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/manager"
@@ -49,47 +47,8 @@ This command is only available for v1 scaffolding project.
 	# Set type to be mutating and operations to be create and update.
 	kubebuilder alpha webhook --group crew --version v1 --kind FirstMate --type=mutating --operations=create,update
 `,
-		Run: func(cmd *cobra.Command, args []string) {
-			dieIfNoProject()
-
-			projectInfo, err := scaffold.LoadProjectFile("PROJECT")
-			if err != nil {
-				log.Fatalf("failed to read the PROJECT file: %v", err)
-			}
-
-			if projectInfo.Version != project.Version1 {
-				fmt.Printf("webhook scaffolding is not supported for this project version: %s \n", projectInfo.Version)
-				os.Exit(1)
-			}
-
-			fmt.Println("Writing scaffold for you to edit...")
-
-			if len(o.res.Resource) == 0 {
-				o.res.Resource = flect.Pluralize(strings.ToLower(o.res.Kind))
-			}
-
-			err = (&scaffold.Scaffold{}).Execute(input.Options{},
-				&manager.Webhook{},
-				&webhook.AdmissionHandler{Resource: o.res, Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
-				&webhook.AdmissionWebhookBuilder{Resource: o.res, Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
-				&webhook.AdmissionWebhooks{Resource: o.res, Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
-				&webhook.AddAdmissionWebhookBuilderHandler{Resource: o.res, Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
-				&webhook.Server{Resource: o.res, Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
-				&webhook.AddServer{Resource: o.res, Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
-			)
-			if err != nil {
-				log.Fatal(err)
-			}
-
-			if o.doMake {
-				fmt.Println("Running make...")
-				cm := exec.Command("make") // #nosec
-				cm.Stderr = os.Stderr
-				cm.Stdout = os.Stdout
-				if err := cm.Run(); err != nil {
-					log.Fatal(err)
-				}
-			}
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run()
 		},
 	}
 	cmd.Flags().StringVar(&o.server, "server", "default",
@@ -113,6 +72,49 @@ type webhookOptions struct {
 	doMake      bool
 }
 
+func (o *webhookOptions) run() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+
+	projectInfo, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+
+	if projectInfo.Version != project.Version1 {
+		return fmt.Errorf("webhook scaffolding is not supported for this project version: %s", projectInfo.Version)
+	}
+
+	if err := o.res.Validate(); err != nil {
+		return withKind(kindInvalidResource, err)
+	}
+
+	fmt.Println("Writing scaffold for you to edit...")
+
+	config := webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}
+	err = filesystem.Transaction(func() error {
+		return (&scaffold.Scaffold{}).Execute(input.Options{},
+			&manager.Webhook{},
+			&webhook.AdmissionHandler{Resource: o.res, Config: config},
+			&webhook.AdmissionWebhookBuilder{Resource: o.res, Config: config},
+			&webhook.AdmissionWebhooks{Resource: o.res, Config: config},
+			&webhook.AddAdmissionWebhookBuilderHandler{Resource: o.res, Config: config},
+			&webhook.Server{Resource: o.res, Config: config},
+			&webhook.AddServer{Resource: o.res, Config: config},
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.doMake {
+		fmt.Println("Running make...")
+		return util.RunCommand(exec.Command("make")) // #nosec
+	}
+	return nil
+}
+
 // gvkForFlags registers flags for Resource fields and returns the Resource
 func gvkForFlags(f *flag.FlagSet) *resource.Resource {
 	r := &resource.Resource{}
@@ -18,20 +18,11 @@ package main
 
 import (
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
-	"strings"
 
-	"github.com/gobuffalo/flect"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
-	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
-	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
-	resourcev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
-	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
 func newWebhookV2Cmd() *cobra.Command {
@@ -47,62 +38,8 @@ func newWebhookV2Cmd() *cobra.Command {
 	# Create conversion webhook for CRD of group crew, version v1 and kind FirstMate.
 	kubebuilder create webhook --group crew --version v1 --kind FirstMate --conversion
 `,
-		Run: func(cmd *cobra.Command, args []string) {
-			dieIfNoProject()
-
-			projectInfo, err := scaffold.LoadProjectFile("PROJECT")
-			if err != nil {
-				log.Fatalf("failed to read the PROJECT file: %v", err)
-			}
-
-			if projectInfo.Version != project.Version2 {
-				fmt.Printf("kubebuilder webhook is for project version: 2, the version of this project is: %s \n", projectInfo.Version)
-				os.Exit(1)
-			}
-
-			if !o.defaulting && !o.validation && !o.conversion {
-				fmt.Printf("kubebuilder webhook requires at least one of --defaulting, --programmatic-validation and --conversion to be true")
-				os.Exit(1)
-			}
-
-			if len(o.res.Resource) == 0 {
-				o.res.Resource = flect.Pluralize(strings.ToLower(o.res.Kind))
-			}
-
-			fmt.Println("Writing scaffold for you to edit...")
-			fmt.Println(filepath.Join("api", o.res.Version,
-				fmt.Sprintf("%s_webhook.go", strings.ToLower(o.res.Kind))))
-			if o.conversion {
-				fmt.Println(`Webhook server has been set up for you.
-You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
-			}
-			webhookScaffolder := &webhook.Webhook{
-				Resource:   o.res,
-				Defaulting: o.defaulting,
-				Validating: o.validation,
-			}
-			err = (&scaffold.Scaffold{}).Execute(
-				input.Options{},
-				webhookScaffolder,
-			)
-			if err != nil {
-				fmt.Printf("error scaffolding webhook: %v", err)
-				os.Exit(1)
-			}
-
-			err = (&resourcev2.Main{}).Update(
-				&resourcev2.MainUpdateOptions{
-					Project:        &projectInfo,
-					WireResource:   false,
-					WireController: false,
-					WireWebhook:    true,
-					Resource:       o.res,
-				})
-			if err != nil {
-				fmt.Printf("error updating main.go: %v", err)
-				os.Exit(1)
-			}
-
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.output.run(o.run)
 		},
 	}
 	o.res = gvkForFlags(cmd.Flags())
@@ -112,6 +49,8 @@ You need to implement the conversion.Hub and conversion.Convertible interfaces f
 		"if set, scaffold the validating webhook")
 	cmd.Flags().BoolVar(&o.conversion, "conversion", false,
 		"if set, scaffold the conversion webhook")
+	o.dryRun.bindFlags(cmd.Flags())
+	o.output.bindFlags(cmd.Flags())
 
 	return cmd
 }
@@ -122,4 +61,45 @@ type webhookV2Options struct {
 	defaulting bool
 	validation bool
 	conversion bool
+
+	dryRun dryRunOptions
+	output outputOptions
+}
+
+func (o *webhookV2Options) run() error {
+	if err := checkProject(); err != nil {
+		return err
+	}
+
+	if err := o.dryRun.validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+
+	if err := o.res.Validate(); err != nil {
+		return withKind(kindInvalidResource, err)
+	}
+	webhookScaffolder := &scaffold.Webhook{
+		Resource:   o.res,
+		Defaulting: o.defaulting,
+		Validating: o.validation,
+		Conversion: o.conversion,
+	}
+	if err := webhookScaffolder.Validate(); err != nil {
+		return withKind(kindInvalidFlags, err)
+	}
+
+	fmt.Println("Writing scaffold for you to edit...")
+	if o.conversion {
+		fmt.Println(`Webhook server has been set up for you.
+You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
+	}
+	changes, err := o.dryRun.run(webhookScaffolder.Scaffold)
+	if err != nil {
+		return err
+	}
+	o.output.recordChanges(changes, o.dryRun.dryRun)
+	if !o.dryRun.dryRun {
+		printPaths(webhookScaffolder.EditPaths)
+	}
+	return nil
 }
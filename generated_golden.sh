@@ -57,6 +57,19 @@ scaffold_test_project() {
 		$kb create api --group core --version v1 --kind Namespace --example=false --controller=true --resource=false --namespaced=false --make=false
 		$kb alpha webhook --group core --version v1 --kind Namespace --type=mutating --operations=update --make=false
 		$kb create api --group policy --version v1beta1 --kind HealthCheckPolicy --example=false --controller=true --resource=true --namespaced=false --make=false
+	elif [ $version == "2" ] && [ $project == "project-v2-multigroup" ]; then
+		export GO111MODULE=on
+		export PATH=$PATH:$(go env GOPATH)/bin
+		go mod init sigs.k8s.io/kubebuilder/testdata/$project  # our repo autodetection will traverse up to the kb module if we don't do this
+
+		$kb init --project-version $version --multigroup --domain testproject.org --license apache2 --owner "The Kubernetes authors"
+		$kb create api --group crew --version v1 --kind Captain --controller=true --resource=true --make=false
+		$kb create webhook --group crew --version v1 --kind Captain --defaulting --programmatic-validation
+		$kb create api --group ship --version v1beta1 --kind Frigate --example=false --controller=true --resource=true --make=false
+		$kb create webhook --group ship --version v1beta1 --kind Frigate --conversion
+		$kb create api --group creatures --version v2alpha1 --kind Kraken --namespaced=false --example=false --controller=true --resource=true --make=false
+		$kb create api --group core --version v1 --kind Namespace --example=false --controller=true --resource=false --namespaced=false --make=false
+		$kb create api --group policy --version v1beta1 --kind HealthCheckPolicy --example=false --controller=true --resource=true --namespaced=false --make=false
 	elif [ $version == "2" ]; then
 		export GO111MODULE=on
 		export PATH=$PATH:$(go env GOPATH)/bin
@@ -67,11 +80,7 @@ scaffold_test_project() {
 		$kb create webhook --group crew --version v1 --kind Captain --defaulting --programmatic-validation
 		$kb create api --group crew --version v1 --kind FirstMate --controller=true --resource=true --make=false
 		$kb create webhook --group crew --version v1 --kind FirstMate --conversion
-		# TODO(droot): Adding a second group is a valid test case and kubebuilder is expected to report an error in this case. It
-		# doesn't do that currently so leaving it commented so that we can enable it later.
-		# $kb create api --group ship --version v1beta1 --kind Frigate --example=false --controller=true --resource=true --make=false
 		$kb create api --group core --version v1 --kind Namespace --example=false --controller=true --resource=false --namespaced=false --make=false
-		# $kb create api --group policy --version v1beta1 --kind HealthCheckPolicy --example=false --controller=true --resource=true --namespaced=false --make=false
 	fi
 	make all test # v2 doesn't test by default
 	rm -f Gopkg.lock
@@ -86,3 +95,4 @@ set -e
 build_kb
 scaffold_test_project gopath/src/project 1
 scaffold_test_project project-v2 2
+scaffold_test_project project-v2-multigroup 2
@@ -22,7 +22,7 @@ import (
 	"strings"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
-	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/controller"
 	resourcev1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
 	resourcev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
@@ -43,6 +43,10 @@ type API struct {
 
 	// DoController indicates whether to scaffold controller files or not
 	DoController bool
+
+	// EditPaths are the paths of the scaffolded files for the user to edit,
+	// set by Scaffold
+	EditPaths []string
 }
 
 // Validate validates whether API scaffold has correct bits to generate
@@ -51,6 +55,9 @@ func (api *API) Validate() error {
 	if err := api.setDefaults(); err != nil {
 		return err
 	}
+	if _, err := GetLayout(api.project.Version); err != nil {
+		return err
+	}
 	if api.Resource.Group == "" {
 		return fmt.Errorf("missing group information for resource")
 	}
@@ -60,7 +67,7 @@ func (api *API) Validate() error {
 	if api.Resource.Kind == "" {
 		return fmt.Errorf("missing kind information for resource")
 	}
-	return nil
+	return api.Resource.Validate()
 }
 
 func (api *API) setDefaults() error {
@@ -79,37 +86,27 @@ func (api *API) Scaffold() error {
 		return err
 	}
 
-	switch ver := api.project.Version; ver {
-	case project.Version1:
-		return api.scaffoldV1()
-	case project.Version2:
-		return api.scaffoldV2()
-	default:
-		return fmt.Errorf("")
+	l, err := GetLayout(api.project.Version)
+	if err != nil {
+		return err
 	}
+	api.EditPaths = nil
+	return l.ScaffoldAPI(api, api.project)
 }
 
-func (api *API) scaffoldV1() error {
+// ScaffoldV1 scaffolds the API in a project of version 1, it is the
+// ScaffoldAPI of its layout.
+func (api *API) ScaffoldV1(_ *input.ProjectFile) error {
 	r := api.Resource
 
 	if api.DoResource {
-		fmt.Println(filepath.Join("pkg", "apis", r.Group, r.Version,
-			fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))))
-		fmt.Println(filepath.Join("pkg", "apis", r.Group, r.Version,
-			fmt.Sprintf("%s_types_test.go", strings.ToLower(r.Kind))))
-
-		err := (&Scaffold{}).Execute(input.Options{},
-			&resourcev1.Register{Resource: r},
-			&resourcev1.Types{Resource: r},
-			&resourcev1.VersionSuiteTest{Resource: r},
-			&resourcev1.TypesTest{Resource: r},
-			&resourcev1.Doc{Resource: r},
-			&resourcev1.Group{Resource: r},
-			&resourcev1.AddToScheme{Resource: r},
-			&resourcev1.CRDSample{Resource: r},
-		)
+		api.EditPaths = append(api.EditPaths,
+			filepath.Join("pkg", "apis", r.Group, r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))),
+			filepath.Join("pkg", "apis", r.Group, r.Version, fmt.Sprintf("%s_types_test.go", strings.ToLower(r.Kind))))
+
+		err := (&Scaffold{}).Execute(input.Options{}, v1APIFiles(r)...)
 		if err != nil {
-			return fmt.Errorf("error scaffolding APIs: %v", err)
+			return withContext("error scaffolding APIs", err)
 		}
 	} else {
 		// disable generation of example reconcile body if not scaffolding resource
@@ -120,50 +117,68 @@ func (api *API) scaffoldV1() error {
 	}
 
 	if api.DoController {
-		fmt.Println(filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
-			fmt.Sprintf("%s_controller.go", strings.ToLower(r.Kind))))
-		fmt.Println(filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
-			fmt.Sprintf("%s_controller_test.go", strings.ToLower(r.Kind))))
-
-		err := (&Scaffold{}).Execute(input.Options{},
-			&controller.Controller{Resource: r},
-			&controller.AddController{Resource: r},
-			&controller.Test{Resource: r},
-			&controller.SuiteTest{Resource: r},
-		)
+		api.EditPaths = append(api.EditPaths,
+			filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
+				fmt.Sprintf("%s_controller.go", strings.ToLower(r.Kind))),
+			filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
+				fmt.Sprintf("%s_controller_test.go", strings.ToLower(r.Kind))))
+
+		err := (&Scaffold{}).Execute(input.Options{}, v1ControllerFiles(r)...)
 		if err != nil {
-			return fmt.Errorf("error scaffolding controller: %v", err)
+			return withContext("error scaffolding controller", err)
 		}
 	}
 
 	return nil
 }
 
-func (api *API) scaffoldV2() error {
+// v1APIFiles are the files of the types of r in a version 1 project.
+func v1APIFiles(r *resourcev1.Resource) []input.File {
+	return []input.File{
+		&resourcev1.Register{Resource: r},
+		&resourcev1.Types{Resource: r},
+		&resourcev1.VersionSuiteTest{Resource: r},
+		&resourcev1.TypesTest{Resource: r},
+		&resourcev1.Doc{Resource: r},
+		&resourcev1.Group{Resource: r},
+		&resourcev1.AddToScheme{Resource: r},
+		&resourcev1.CRDSample{Resource: r},
+	}
+}
+
+// v1ControllerFiles are the files of the controller of r in a version 1
+// project.
+func v1ControllerFiles(r *resourcev1.Resource) []input.File {
+	return []input.File{
+		&controller.Controller{Resource: r},
+		&controller.AddController{Resource: r},
+		&controller.Test{Resource: r},
+		&controller.SuiteTest{Resource: r},
+	}
+}
+
+// ScaffoldV2 scaffolds the API in a project of version 2 and wires it in
+// main.go, it is the ScaffoldAPI of its layout.
+func (api *API) ScaffoldV2(p *input.ProjectFile) error {
 	r := api.Resource
 
 	if api.DoResource {
-		if err := api.validateResourceGroup(r); err != nil {
+		if err := validateResourceGroup(p, r); err != nil {
 			return err
 		}
 
-		fmt.Println(filepath.Join("api", r.Version,
-			fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))))
+		api.EditPaths = append(api.EditPaths, util.TypesPath(r, p.MultiGroup))
 
 		err := (&Scaffold{}).Execute(
 			input.Options{},
-			&resourcev2.Types{
-				Input: input.Input{
-					Path: filepath.Join("api", r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))),
-				},
-				Resource: r},
+			&resourcev2.Types{Resource: r},
 			&resourcev2.Group{Resource: r},
 			&resourcev2.CRDSample{Resource: r},
 			&crdv2.EnableWebhookPatch{Resource: r},
 			&crdv2.EnableCAInjectionPatch{Resource: r},
 		)
 		if err != nil {
-			return fmt.Errorf("error scaffolding APIs: %v", err)
+			return withContext("error scaffolding APIs", err)
 		}
 
 		crdKustomization := &crdv2.Kustomization{Resource: r}
@@ -173,7 +188,7 @@ func (api *API) scaffoldV2() error {
 			&crdv2.KustomizeConfig{},
 		)
 		if err != nil && !isAlreadyExistsError(err) {
-			return fmt.Errorf("error scaffolding kustomization: %v", err)
+			return withContext("error scaffolding kustomization", err)
 		}
 
 		err = crdKustomization.Update()
@@ -181,14 +196,6 @@ func (api *API) scaffoldV2() error {
 			return fmt.Errorf("error updating kustomization.yaml: %v", err)
 		}
 
-		// update scaffolded resource in project file
-		api.project.Resources = append(api.project.Resources,
-			input.Resource{Group: r.Group, Version: r.Version, Kind: r.Kind})
-		err = saveProjectFile("PROJECT", api.project)
-		if err != nil {
-			fmt.Printf("error updating project file with resource information : %v \n", err)
-		}
-
 	} else {
 		// disable generation of example reconcile body if not scaffolding resource
 		// because this could result in a fork-bomb of k8s resources where watching a
@@ -198,7 +205,8 @@ func (api *API) scaffoldV2() error {
 	}
 
 	if api.DoController {
-		fmt.Println(filepath.Join("controllers", fmt.Sprintf("%s_controller.go", strings.ToLower(r.Kind))))
+		api.EditPaths = append(api.EditPaths, filepath.Join(util.ControllersDir(r.Group, p.MultiGroup),
+			fmt.Sprintf("%s_controller.go", strings.ToLower(r.Kind))))
 
 		ctrlScaffolder := &resourcev2.Controller{Resource: r}
 		testsuiteScaffolder := &resourcev2.ControllerSuiteTest{Resource: r}
@@ -208,36 +216,80 @@ func (api *API) scaffoldV2() error {
 			ctrlScaffolder,
 		)
 		if err != nil {
-			return fmt.Errorf("error scaffolding controller: %v", err)
+			return withContext("error scaffolding controller", err)
 		}
 
 		err = testsuiteScaffolder.Update()
 		if err != nil {
-			return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+			return withContext("error updating suite_test.go under controllers pkg", err)
+		}
+	}
+
+	if api.DoResource || api.DoController {
+		// update scaffolded resource in project file
+		res := recordResource(p, r)
+		if api.DoResource {
+			res.API = &input.ResourceAPI{Namespaced: r.Namespaced}
+			res.Core, res.External = false, false
+		} else if res.API == nil && !exists(util.TypesPath(r, p.MultiGroup)) {
+			// the controller uses the types of a core group from
+			// k8s.io/api, or types from outside of the project
+			res.Core = util.IsCoreGroup(r.Group)
+			res.External = !res.Core
+		}
+		if api.DoController {
+			res.Controller = true
+		}
+		err := saveProjectFile("PROJECT", p)
+		if err != nil {
+			return fmt.Errorf("error updating project file with resource information: %v", err)
 		}
 	}
 
 	err := (&resourcev2.Main{}).Update(
 		&resourcev2.MainUpdateOptions{
-			Project:        api.project,
+			Project:        p,
 			WireResource:   api.DoResource,
 			WireController: api.DoController,
 			Resource:       r,
 		})
 	if err != nil {
-		return fmt.Errorf("error updating main.go: %v", err)
+		return withContext("error updating main.go", err)
 	}
 
 	return nil
 }
 
-// Since we support single group only in v2 scaffolding, validate if resource
-// being created belongs to existing group.
-func (api *API) validateResourceGroup(resource *resourcev1.Resource) error {
-	for _, existingGroup := range api.project.ResourceGroups() {
+// Since projects without the multi group layout have the packages of all
+// groups in the same directories, validate if resource being created belongs
+// to existing group.
+func validateResourceGroup(p *input.ProjectFile, resource *resourcev1.Resource) error {
+	if p.MultiGroup {
+		return nil
+	}
+	for _, existingGroup := range p.ResourceGroups() {
 		if strings.ToLower(resource.Group) != strings.ToLower(existingGroup) {
-			return fmt.Errorf("Group '%s' is not same as existing group '%s'. Multiple groups are not supported yet.", resource.Group, existingGroup)
+			return fmt.Errorf("Group '%s' is not same as existing group '%s'. Multiple groups need the multi group layout: "+
+				"set 'multigroup: true' in PROJECT and move api/<version> to api/%s/<version> and controllers to controllers/%s",
+				resource.Group, existingGroup, existingGroup, existingGroup)
 		}
 	}
 	return nil
 }
+
+// recordResource returns the entry of r in the resources of the project file,
+// which is added if the project has no entry for r yet.
+func recordResource(p *input.ProjectFile, r *resourcev1.Resource) *input.Resource {
+	for i := range p.Resources {
+		if p.Resources[i].Is(r.Group, r.Version, r.Kind) {
+			return &p.Resources[i]
+		}
+	}
+	p.Resources = append(p.Resources, input.Resource{
+		Group:   r.Group,
+		Version: r.Version,
+		Kind:    r.Kind,
+		Plural:  r.Resource,
+	})
+	return &p.Resources[len(p.Resources)-1]
+}
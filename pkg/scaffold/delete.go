@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	resourcev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Delete removes the resource of api from the project with its controller
+// and webhooks, reverting the scaffolding of 'create api' and
+// 'create webhook'.
+func (api *API) Delete() error {
+	if err := api.setDefaults(); err != nil {
+		return err
+	}
+
+	l, err := GetLayout(api.project.Version)
+	if err != nil {
+		return err
+	}
+	if l.DeleteAPI == nil {
+		return fmt.Errorf("kubebuilder delete api is not supported for project version %s", api.project.Version)
+	}
+	return l.DeleteAPI(api, api.project)
+}
+
+// DeleteV2 removes the API from a project of version 2, it is the DeleteAPI
+// of its layout.
+func (api *API) DeleteV2(p *input.ProjectFile) error {
+	r := api.Resource
+
+	found := -1
+	// the group version is only removed with its last resource
+	lastOfVersion := true
+	for i, res := range p.Resources {
+		if res.Group != r.Group || res.Version != r.Version {
+			continue
+		}
+		if res.Kind == r.Kind {
+			found = i
+		} else if res.API != nil {
+			lastOfVersion = false
+		}
+	}
+	if found < 0 {
+		return fmt.Errorf("resource %s/%s, Kind=%s is not in the project", r.Group, r.Version, r.Kind)
+	}
+
+	s := &Scaffold{BoilerplateOptional: true}
+	if err := s.defaultOptions(&input.Options{}); err != nil {
+		return err
+	}
+
+	// the files scaffolded for the resource are removed, the CRD
+	// kustomization and the suite test are edited
+	crdKustomization := &crdv2.Kustomization{Resource: r}
+	suiteTest := &resourcev2.ControllerSuiteTest{Resource: r}
+	controller := &resourcev2.Controller{Resource: r}
+	files := []input.File{
+		&resourcev2.Types{Resource: r},
+		&resourcev2.CRDSample{Resource: r},
+		&crdv2.EnableWebhookPatch{Resource: r},
+		&crdv2.EnableCAInjectionPatch{Resource: r},
+		controller,
+		&webhook.Webhook{Resource: r},
+	}
+	if lastOfVersion {
+		files = append(files, &resourcev2.Group{Resource: r})
+	}
+	var paths []string
+	for i, f := range append(files, crdKustomization, suiteTest) {
+		path, err := s.path(f)
+		if err != nil {
+			return err
+		}
+		if i < len(files) {
+			paths = append(paths, path)
+		}
+	}
+
+	// the generated files, the CRD path depends on the types file
+	paths = append(paths, crdKustomization.CRDPath())
+	deepcopyPath := filepath.Join(util.APIDir(r, p.MultiGroup), "zz_generated.deepcopy.go")
+	if lastOfVersion {
+		paths = append(paths, deepcopyPath)
+	} else if exists(deepcopyPath) {
+		err := resourcev2.RemoveMethods(deepcopyPath,
+			r.Kind, r.Kind+"List", r.Kind+"Spec", r.Kind+"Status")
+		if err != nil {
+			return fmt.Errorf("error updating %s: %v", deepcopyPath, err)
+		}
+	}
+
+	if exists(crdKustomization.Path) {
+		if err := crdKustomization.Remove(); err != nil {
+			return fmt.Errorf("error updating kustomization.yaml: %v", err)
+		}
+	}
+
+	err := (&resourcev2.Main{}).Remove(
+		&resourcev2.MainUpdateOptions{
+			Project:        p,
+			WireResource:   lastOfVersion,
+			WireController: true,
+			WireWebhook:    true,
+			Resource:       r,
+		})
+	if err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+
+	if exists(suiteTest.Path) {
+		// the suite test is removed with the last controller of its package
+		controllers, err := afero.Glob(filesystem.Fs,
+			filepath.Join(filepath.Dir(suiteTest.Path), "*_controller.go"))
+		if err != nil {
+			return err
+		}
+		otherControllers := false
+		for _, c := range controllers {
+			if c != controller.Path {
+				otherControllers = true
+			}
+		}
+		if !otherControllers {
+			paths = append(paths, suiteTest.Path)
+		} else if lastOfVersion {
+			if err := suiteTest.Remove(); err != nil {
+				return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+			}
+		}
+	}
+
+	if err := removeFiles(paths...); err != nil {
+		return err
+	}
+
+	p.Resources = append(p.Resources[:found], p.Resources[found+1:]...)
+	if err := saveProjectFile("PROJECT", p); err != nil {
+		return fmt.Errorf("error updating project file with resource information: %v", err)
+	}
+	return nil
+}
+
+// exists returns true if the file at path exists.
+func exists(path string) bool {
+	_, err := filesystem.Fs.Stat(path)
+	return err == nil
+}
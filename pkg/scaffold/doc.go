@@ -15,4 +15,12 @@ limitations under the License.
 */
 
 // Package scaffold contains libraries for scaffolding code to use with controller-runtime
+//
+// The project layouts are registered by their packages, which programs using
+// the library import for their side effect:
+//
+//	import (
+//		_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v1"
+//		_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v2"
+//	)
 package scaffold
@@ -0,0 +1,245 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor checks a project for problems that break scaffolding or
+// building it.
+package doctor
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+// Severity is the severity of a problem
+type Severity string
+
+const (
+	// Error problems break scaffolding or building the project
+	Error Severity = "error"
+
+	// Warning problems may break some workflows
+	Warning Severity = "warning"
+)
+
+// Problem is a problem found in a project
+type Problem struct {
+	// Check is the name of the check that found the problem
+	Check string `json:"check"`
+
+	Severity Severity `json:"severity"`
+
+	// Message describes the problem
+	Message string `json:"message"`
+
+	// Fix suggests how to fix the problem
+	Fix string `json:"fix"`
+}
+
+// lookPath finds the tools on the PATH, replaced in tests
+var lookPath = exec.LookPath
+
+// Check checks the project in the current directory and returns the problems
+// found, errors first.
+func Check() []Problem {
+	p, problems := checkProject()
+	if p == nil {
+		return problems
+	}
+
+	problems = append(problems, checkBoilerplate()...)
+	problems = append(problems, checkTools(p)...)
+	if p.Version == project.Version2 {
+		problems = append(problems, checkInsertionPoints(p)...)
+		problems = append(problems, checkResources(p)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Severity == Error && problems[j].Severity != Error
+	})
+	return problems
+}
+
+// HasErrors returns true if any of the problems is an error
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProject loads the PROJECT file, the returned project is nil if it
+// cannot be used for the other checks.
+func checkProject() (*input.ProjectFile, []Problem) {
+	p, err := scaffold.ParseProjectFile("PROJECT")
+	if err != nil {
+		return nil, []Problem{{
+			Check:    "project",
+			Severity: Error,
+			Message:  fmt.Sprintf("cannot read the PROJECT file: %v", err),
+			Fix: fmt.Sprintf("run kubebuilder in the root directory of the project, or correct PROJECT to the schema of its version, one of %s",
+				strings.Join(scaffold.LayoutVersions(), ", ")),
+		}}
+	}
+
+	var problems []Problem
+	for _, e := range scaffold.ValidateProjectFile(&p) {
+		problems = append(problems, Problem{
+			Check:    "project",
+			Severity: Error,
+			Message:  fmt.Sprintf("PROJECT has an invalid %s: %s", e.Field, e.Message),
+			Fix:      fmt.Sprintf("correct %s in PROJECT", e.Field),
+		})
+	}
+	return &p, problems
+}
+
+func checkBoilerplate() []Problem {
+	path := filepath.Join("hack", "boilerplate.go.txt")
+	if exists, _ := afero.Exists(filesystem.Fs, path); exists {
+		return nil
+	}
+	return []Problem{{
+		Check:    "boilerplate",
+		Severity: Error,
+		Message:  fmt.Sprintf("%s is missing", path),
+		Fix:      fmt.Sprintf("create %s with the license header of the generated files, it may be empty", path),
+	}}
+}
+
+func checkTools(p *input.ProjectFile) []Problem {
+	type tool struct {
+		name string
+		fix  string
+	}
+	tools := []tool{{"go", "install Go, see https://golang.org/doc/install"}}
+	switch p.Version {
+	case project.Version1:
+		tools = append(tools,
+			tool{"dep", "install dep, see https://golang.github.io/dep/docs/installation.html"})
+	case project.Version2:
+		tools = append(tools,
+			tool{"controller-gen", "run 'make controller-gen' to download it"},
+			tool{"kustomize", "install kustomize, see https://github.com/kubernetes-sigs/kustomize"})
+	}
+
+	var problems []Problem
+	for _, t := range tools {
+		if _, err := lookPath(t.name); err != nil {
+			severity := Warning
+			if t.name == "go" {
+				severity = Error
+			}
+			problems = append(problems, Problem{
+				Check:    "tools",
+				Severity: severity,
+				Message:  fmt.Sprintf("%s is not on the PATH", t.name),
+				Fix:      t.fix,
+			})
+		}
+	}
+	return problems
+}
+
+func checkInsertionPoints(p *input.ProjectFile) []Problem {
+	var problems []Problem
+	for _, ip := range scaffoldv2.InsertionPoints(p) {
+		exists, err := afero.Exists(filesystem.Fs, ip.Path)
+		if err == nil && !exists {
+			// only main.go is required, the other files are scaffolded later
+			if ip.Path == "main.go" {
+				problems = append(problems, Problem{
+					Check:    "insertion-points",
+					Severity: Error,
+					Message:  "main.go is missing",
+					Fix:      "restore main.go, 'kubebuilder alpha rescaffold' renders it again",
+				})
+				break
+			}
+			continue
+		}
+		if err == nil {
+			err = ip.Check()
+		}
+		if err != nil {
+			where := ip.Path
+			if ip.Func != "" {
+				where = fmt.Sprintf("function %s of %s", ip.Func, ip.Path)
+			}
+			problems = append(problems, Problem{
+				Check:    "insertion-points",
+				Severity: Error,
+				Message:  err.Error(),
+				Fix:      fmt.Sprintf("add the comment %q to %s where kubebuilder should insert code", ip.Marker, where),
+			})
+		}
+	}
+	return problems
+}
+
+func checkResources(p *input.ProjectFile) []Problem {
+	var problems []Problem
+	kinds := map[string]bool{}
+	for _, r := range p.Resources {
+		if r.API == nil {
+			continue
+		}
+		path := util.TypesPath(&resource.Resource{Group: r.Group, Version: r.Version, Kind: r.Kind}, p.MultiGroup)
+		kinds[path] = true
+		if exists, _ := afero.Exists(filesystem.Fs, path); !exists {
+			problems = append(problems, Problem{
+				Check:    "resources",
+				Severity: Error,
+				Message:  fmt.Sprintf("resource %s/%s %s in PROJECT has no %s", r.Group, r.Version, r.Kind, path),
+				Fix:      "restore the file, or remove the resource from PROJECT",
+			})
+		}
+	}
+
+	pattern := filepath.Join("api", "*", "*_types.go")
+	if p.MultiGroup {
+		pattern = filepath.Join("api", "*", "*", "*_types.go")
+	}
+	paths, err := afero.Glob(filesystem.Fs, pattern)
+	if err != nil {
+		return problems
+	}
+	for _, path := range paths {
+		if !kinds[path] {
+			problems = append(problems, Problem{
+				Check:    "resources",
+				Severity: Warning,
+				Message:  fmt.Sprintf("%s is not a resource in PROJECT", path),
+				Fix:      "add the resource to the resources of PROJECT, or remove the file",
+			})
+		}
+	}
+	return problems
+}
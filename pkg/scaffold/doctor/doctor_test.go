@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v1" // register the layouts
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v2"
+)
+
+const mainGo = `package main
+
+func init() {
+	// +kubebuilder:scaffold:scheme
+}
+
+func main() {
+	// +kubebuilder:scaffold:builder
+}
+`
+
+func setup(t *testing.T, files map[string]string, tools ...string) func() {
+	oldFs, oldLookPath := filesystem.Fs, lookPath
+	filesystem.Fs = afero.NewMemMapFs()
+	lookPath = func(file string) (string, error) {
+		for _, tool := range tools {
+			if tool == file {
+				return "/bin/" + file, nil
+			}
+		}
+		return "", fmt.Errorf("%s not found", file)
+	}
+	for path, content := range files {
+		if err := afero.WriteFile(filesystem.Fs, path, []byte(content), 0600); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+	return func() { filesystem.Fs, lookPath = oldFs, oldLookPath }
+}
+
+func TestCheckHealthyProject(t *testing.T) {
+	defer setup(t, map[string]string{
+		"PROJECT": `version: "2"
+domain: example.com
+repo: example.com/project
+resources:
+- group: ship
+  version: v1
+  kind: Frigate
+`,
+		"hack/boilerplate.go.txt":   "",
+		"main.go":                   mainGo,
+		"api/v1/frigate_types.go":   "package v1\n",
+		"controllers/suite_test.go": "package controllers\n\n// +kubebuilder:scaffold:scheme\n",
+	}, "go", "controller-gen", "kustomize")()
+
+	if problems := Check(); len(problems) != 0 {
+		t.Errorf("got problems %v", problems)
+	}
+}
+
+func TestCheckBrokenProject(t *testing.T) {
+	defer setup(t, map[string]string{
+		"PROJECT": `version: "2"
+domain: example.com
+resources:
+- group: ship
+  version: v1
+  kind: Frigate
+`,
+		"main.go":                   strings.Replace(mainGo, "// +kubebuilder:scaffold:builder", "", 1),
+		"api/v1/destroyer_types.go": "package v1\n",
+	}, "go")()
+
+	problems := Check()
+	var got []string
+	for _, p := range problems {
+		got = append(got, fmt.Sprintf("%s %s: %s", p.Severity, p.Check, p.Message))
+		if p.Fix == "" {
+			t.Errorf("problem without fix %v", p)
+		}
+	}
+	expected := []string{
+		"error project: PROJECT has an invalid repo: must be set to the Go import path of the project",
+		"error boilerplate: hack/boilerplate.go.txt is missing",
+		`error insertion-points: cannot find the insertion point "// +kubebuilder:scaffold:builder" in function main of main.go, add it where the code should be inserted`,
+		"error resources: resource ship/v1 Frigate in PROJECT has no api/v1/frigate_types.go",
+		"warning tools: controller-gen is not on the PATH",
+		"warning tools: kustomize is not on the PATH",
+		"warning resources: api/v1/destroyer_types.go is not a resource in PROJECT",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got: %s and wanted: %s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+	if !HasErrors(problems) {
+		t.Errorf("expected errors")
+	}
+}
+
+func TestCheckWithoutProject(t *testing.T) {
+	defer setup(t, nil)()
+
+	problems := Check()
+	if len(problems) != 1 || problems[0].Check != "project" || problems[0].Severity != Error {
+		t.Errorf("got problems %v", problems)
+	}
+}
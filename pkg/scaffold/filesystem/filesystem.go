@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filesystem provides the filesystem the scaffolding reads from and
+// writes to.
+package filesystem
+
+import (
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// Fs is the filesystem used by the scaffolding. It defaults to the OS
+// filesystem and can be replaced, e.g. by an Overlay to preview changes
+// without writing them.
+var Fs afero.Fs = afero.NewOsFs()
+
+// Stage runs fn with Fs replaced by an Overlay on top of it, and returns the
+// Overlay holding the changes made by fn. Nothing is written to the
+// underlying filesystem.
+func Stage(fn func() error) (*Overlay, error) {
+	base := Fs
+	overlay := NewOverlay(base)
+	Fs = overlay
+	defer func() { Fs = base }()
+
+	if err := fn(); err != nil {
+		return nil, err
+	}
+	return overlay, nil
+}
+
+// Transaction runs fn as a single transaction: the changes made by fn are
+// only written if fn succeeds, and are rolled back if writing them fails.
+func Transaction(fn func() error) error {
+	overlay, err := Stage(fn)
+	if err != nil {
+		return err
+	}
+	return overlay.Commit()
+}
+
+// Getwd returns the directory the scaffolding runs in. It defaults to the
+// working directory and can be replaced together with Fs.
+var Getwd = os.Getwd
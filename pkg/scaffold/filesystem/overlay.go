@@ -0,0 +1,346 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/internal/diff"
+)
+
+var _ afero.Fs = &Overlay{}
+
+// Overlay is a filesystem that reads through to a base filesystem and keeps
+// all writes in memory, so they can be inspected before touching the base.
+// Removed files are hidden in the overlay and only removed from the base on
+// commit.
+type Overlay struct {
+	afero.Fs
+
+	base  afero.Fs
+	layer afero.Fs
+
+	// written tracks the paths opened for writing
+	written map[string]struct{}
+
+	// removed tracks the paths removed, which are hidden until they are
+	// written again
+	removed map[string]struct{}
+}
+
+// NewOverlay returns an Overlay on top of base.
+func NewOverlay(base afero.Fs) *Overlay {
+	layer := afero.NewMemMapFs()
+	return &Overlay{
+		Fs:      afero.NewCopyOnWriteFs(base, layer),
+		base:    base,
+		layer:   layer,
+		written: map[string]struct{}{},
+		removed: map[string]struct{}{},
+	}
+}
+
+// Create implements afero.Fs
+func (o *Overlay) Create(name string) (afero.File, error) {
+	if err := o.recreate(name); err != nil {
+		return nil, err
+	}
+	o.written[filepath.Clean(name)] = struct{}{}
+	return o.Fs.Create(name)
+}
+
+// OpenFile implements afero.Fs
+func (o *Overlay) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if o.isRemoved(name) {
+		if flag&os.O_CREATE == 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		if err := o.recreate(name); err != nil {
+			return nil, err
+		}
+	}
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+		o.written[filepath.Clean(name)] = struct{}{}
+	}
+	return o.Fs.OpenFile(name, flag, perm)
+}
+
+// Open implements afero.Fs
+func (o *Overlay) Open(name string) (afero.File, error) {
+	if o.isRemoved(name) {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return o.Fs.Open(name)
+}
+
+// Stat implements afero.Fs
+func (o *Overlay) Stat(name string) (os.FileInfo, error) {
+	if o.isRemoved(name) {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return o.Fs.Stat(name)
+}
+
+// Remove implements afero.Fs. Files of the base are hidden, not removed.
+func (o *Overlay) Remove(name string) error {
+	fi, err := o.Stat(name)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return &os.PathError{Op: "remove", Path: name, Err: fmt.Errorf("removing directories is not supported")}
+	}
+	if exists, err := afero.Exists(o.layer, name); err != nil {
+		return err
+	} else if exists {
+		if err := o.layer.Remove(name); err != nil {
+			return err
+		}
+	}
+	name = filepath.Clean(name)
+	delete(o.written, name)
+	if exists, err := afero.Exists(o.base, name); err != nil {
+		return err
+	} else if exists {
+		o.removed[name] = struct{}{}
+	}
+	return nil
+}
+
+// isRemoved returns true if the file name has been removed.
+func (o *Overlay) isRemoved(name string) bool {
+	_, removed := o.removed[filepath.Clean(name)]
+	return removed
+}
+
+// recreate creates the removed file name empty in the layer, so its content
+// in the base is not copied up when it is written again.
+func (o *Overlay) recreate(name string) error {
+	if !o.isRemoved(name) {
+		return nil
+	}
+	if err := o.layer.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(o.layer, name, nil, 0600); err != nil {
+		return err
+	}
+	delete(o.removed, filepath.Clean(name))
+	return nil
+}
+
+// Name implements afero.Fs
+func (o *Overlay) Name() string {
+	return "Overlay"
+}
+
+// Change is a file that differs between the overlay and its base
+type Change struct {
+	// Path is the path of the file
+	Path string
+
+	// Old is the content in the base, nil if the file is new
+	Old []byte
+
+	// New is the content in the overlay, nil if the file is removed
+	New []byte
+	// mode is the file mode in the base, restored by a rollback
+	mode os.FileMode
+}
+
+// Created returns true if the file does not exist in the base
+func (c Change) Created() bool {
+	return c.Old == nil
+}
+
+// Removed returns true if the file is removed from the base
+func (c Change) Removed() bool {
+	return c.New == nil
+}
+
+// Changes returns the files whose content in the overlay differs from the
+// base, sorted by path.
+func (o *Overlay) Changes() ([]Change, error) {
+	paths := make([]string, 0, len(o.written)+len(o.removed))
+	for path := range o.written {
+		paths = append(paths, path)
+	}
+	for path := range o.removed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var changes []Change
+	for _, path := range paths {
+		c := Change{Path: path}
+		if _, removed := o.removed[path]; !removed {
+			var err error
+			c.New, err = afero.ReadFile(o.layer, path)
+			if err != nil {
+				return nil, err
+			}
+			if c.New == nil {
+				c.New = []byte{}
+			}
+		}
+
+		fi, err := o.base.Stat(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			c.mode = fi.Mode().Perm()
+			c.Old, err = afero.ReadFile(o.base, path)
+			if err != nil {
+				return nil, err
+			}
+			if c.Old == nil {
+				c.Old = []byte{}
+			}
+			if !c.Removed() && bytes.Equal(c.Old, c.New) {
+				continue
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// Diff writes a unified diff of all changes to w.
+func (o *Overlay) Diff(w io.Writer) error {
+	changes, err := o.Changes()
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if err := diff.Unified(w, c.Path, c.Old, c.New, 3); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit writes all changes to the base. If a write fails, the files already
+// written are restored to their original content and new files are removed.
+func (o *Overlay) Commit() error {
+	changes, err := o.Changes()
+	if err != nil {
+		return err
+	}
+
+	var committed []Change
+	var createdDirs []string
+	for _, c := range changes {
+		var err error
+		if c.Removed() {
+			if err = o.base.Remove(c.Path); err == nil {
+				committed = append(committed, c)
+			}
+		} else {
+			var dirs []string
+			dirs, err = o.mkdirAll(filepath.Dir(c.Path))
+			createdDirs = append(createdDirs, dirs...)
+			if err == nil {
+				// a failed write may leave a partially written file behind, so the
+				// change is rolled back as well
+				committed = append(committed, c)
+				err = o.write(c)
+			}
+		}
+		if err != nil {
+			if rerr := o.rollback(committed, createdDirs); rerr != nil {
+				return fmt.Errorf("%v (rollback failed: %v)", err, rerr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes the new content of c to the base, keeping the file mode of
+// existing files.
+func (o *Overlay) write(c Change) error {
+	perm := os.FileMode(0600)
+	if fi, err := o.layer.Stat(c.Path); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	if err := afero.WriteFile(o.base, c.Path, c.New, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %v", c.Path, err)
+	}
+	return nil
+}
+
+// mkdirAll creates dir and its missing parents in the base and returns the
+// directories it created, parents first.
+func (o *Overlay) mkdirAll(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; d != "." && d != string(filepath.Separator); d = filepath.Dir(d) {
+		exists, err := afero.DirExists(o.base, d)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			break
+		}
+		missing = append([]string{d}, missing...)
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	if err := o.base.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+	return missing, nil
+}
+
+// rollback undoes the given committed changes and removes the directories
+// created for them.
+func (o *Overlay) rollback(committed []Change, createdDirs []string) error {
+	var errs []string
+	for i := len(committed) - 1; i >= 0; i-- {
+		c := committed[i]
+		var err error
+		if c.Created() {
+			if err = o.base.Remove(c.Path); os.IsNotExist(err) {
+				err = nil
+			}
+		} else if err = afero.WriteFile(o.base, c.Path, c.Old, c.mode); err == nil {
+			// the mode of an existing file is not changed by writing it
+			err = o.base.Chmod(c.Path, c.mode)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c.Path, err))
+		}
+	}
+	for i := len(createdDirs) - 1; i >= 0; i-- {
+		if err := o.base.Remove(createdDirs[i]); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", createdDirs[i], err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to restore %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestOverlay(t *testing.T) {
+	base := afero.NewMemMapFs()
+	if err := afero.WriteFile(base, "PROJECT", []byte("version: \"2\"\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := afero.WriteFile(base, "main.go", []byte("package main\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	o := NewOverlay(base)
+	if err := afero.WriteFile(o, "PROJECT", []byte("version: \"2\"\ndomain: x\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := afero.WriteFile(o, "main.go", []byte("package main\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := o.MkdirAll("api/v1", 0700); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := afero.WriteFile(o, "api/v1/types.go", []byte("package v1\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// reads see the overlay, the base is untouched
+	b, err := afero.ReadFile(o, "PROJECT")
+	if err != nil || string(b) != "version: \"2\"\ndomain: x\n" {
+		t.Errorf("got: %q, %v", b, err)
+	}
+	b, err = afero.ReadFile(base, "PROJECT")
+	if err != nil || string(b) != "version: \"2\"\n" {
+		t.Errorf("base was modified: %q, %v", b, err)
+	}
+	if exists, _ := afero.Exists(base, "api/v1/types.go"); exists {
+		t.Errorf("base was modified: api/v1/types.go exists")
+	}
+
+	// unchanged writes are not reported
+	changes, err := o.Changes()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, wanted 2: %v", len(changes), changes)
+	}
+	if changes[0].Path != "PROJECT" || changes[0].Created() {
+		t.Errorf("got %s (created: %v), wanted modified PROJECT", changes[0].Path, changes[0].Created())
+	}
+	if changes[1].Path != "api/v1/types.go" || !changes[1].Created() {
+		t.Errorf("got %s (created: %v), wanted created api/v1/types.go", changes[1].Path, changes[1].Created())
+	}
+
+	if err := o.Commit(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	b, err = afero.ReadFile(base, "api/v1/types.go")
+	if err != nil || string(b) != "package v1\n" {
+		t.Errorf("got: %q, %v", b, err)
+	}
+}
+
+// failingFs fails to open the given path for writing
+type failingFs struct {
+	afero.Fs
+	path string
+}
+
+func (f *failingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if filepath.Clean(name) == f.path && flag&os.O_WRONLY != 0 {
+		return nil, fmt.Errorf("injected failure")
+	}
+	return f.Fs.OpenFile(name, flag, perm)
+}
+
+func TestOverlayCommitRollback(t *testing.T) {
+	base := &failingFs{Fs: afero.NewMemMapFs(), path: "main.go"}
+	if err := afero.WriteFile(base.Fs, "PROJECT", []byte("version: \"2\"\n"), 0644); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := afero.WriteFile(base.Fs, "main.go", []byte("package main\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := afero.WriteFile(base.Fs, "hack/run.sh", []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	o := NewOverlay(base)
+	if err := o.Remove("hack/run.sh"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	files := map[string]string{
+		"PROJECT":         "version: \"2\"\ndomain: x\n",
+		"api/v1/types.go": "package v1\n",
+		"main.go":         "package main\n\nfunc main() {}\n",
+	}
+	for path, content := range files {
+		if err := o.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("error %v", err)
+		}
+		if err := afero.WriteFile(o, path, []byte(content), 0600); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+
+	if err := o.Commit(); err == nil {
+		t.Fatalf("expected commit to fail")
+	}
+
+	b, err := afero.ReadFile(base, "PROJECT")
+	if err != nil || string(b) != "version: \"2\"\n" {
+		t.Errorf("PROJECT was not restored: %q, %v", b, err)
+	}
+	b, err = afero.ReadFile(base, "main.go")
+	if err != nil || string(b) != "package main\n" {
+		t.Errorf("main.go was not restored: %q, %v", b, err)
+	}
+	b, err = afero.ReadFile(base, "hack/run.sh")
+	if err != nil || string(b) != "#!/bin/sh\n" {
+		t.Errorf("hack/run.sh was not restored: %q, %v", b, err)
+	}
+	for path, mode := range map[string]os.FileMode{"PROJECT": 0644, "hack/run.sh": 0755} {
+		if fi, err := base.Stat(path); err != nil || fi.Mode().Perm() != mode {
+			t.Errorf("the mode of %s was not restored: %v, %v", path, fi.Mode(), err)
+		}
+	}
+	for _, path := range []string{"api/v1/types.go", "api/v1", "api"} {
+		if exists, _ := afero.Exists(base, path); exists {
+			t.Errorf("%s was not removed", path)
+		}
+	}
+}
+
+func TestOverlayRemove(t *testing.T) {
+	base := afero.NewMemMapFs()
+	for _, path := range []string{"a.go", "b.go", "c.go"} {
+		if err := afero.WriteFile(base, path, []byte("package "+path[:1]+"\n"), 0600); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+
+	o := NewOverlay(base)
+	for _, path := range []string{"a.go", "b.go"} {
+		if err := o.Remove(path); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+	if err := o.Remove("missing.go"); !os.IsNotExist(err) {
+		t.Errorf("expected removing a missing file to fail, got %v", err)
+	}
+	// a removed file written again starts empty
+	f, err := o.OpenFile("b.go", os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if _, err := f.Write([]byte("package bb\n")); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	f.Close()
+
+	// removed files are hidden in the overlay only
+	if exists, _ := afero.Exists(o, "a.go"); exists {
+		t.Errorf("a.go is visible in the overlay")
+	}
+	if _, err := afero.ReadFile(o, "a.go"); !os.IsNotExist(err) {
+		t.Errorf("expected a.go to be missing, got %v", err)
+	}
+	if exists, _ := afero.Exists(base, "a.go"); !exists {
+		t.Errorf("a.go was removed from the base")
+	}
+
+	changes, err := o.Changes()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if len(changes) != 2 || !changes[0].Removed() || changes[1].Removed() || string(changes[1].New) != "package bb\n" {
+		t.Fatalf("expected a.go removed and b.go modified, got %+v", changes)
+	}
+
+	if err := o.Commit(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if exists, _ := afero.Exists(base, "a.go"); exists {
+		t.Errorf("a.go was not removed from the base")
+	}
+	if b, _ := afero.ReadFile(base, "b.go"); string(b) != "package bb\n" {
+		t.Errorf("got b.go %q", b)
+	}
+	if exists, _ := afero.Exists(base, "c.go"); !exists {
+		t.Errorf("c.go was removed from the base")
+	}
+}
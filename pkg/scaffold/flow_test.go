@@ -0,0 +1,624 @@
+package scaffold_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+var _ = Describe("Scaffolding a project in memory", func() {
+	var oldFs afero.Fs
+	var oldGetwd func() (string, error)
+
+	BeforeEach(func() {
+		oldFs, oldGetwd = filesystem.Fs, filesystem.Getwd
+		filesystem.Fs = afero.NewMemMapFs()
+		filesystem.Getwd = func() (string, error) { return "/memory/project", nil }
+	})
+
+	AfterEach(func() {
+		filesystem.Fs, filesystem.Getwd = oldFs, oldGetwd
+	})
+
+	// initProject scaffolds a new project of the version with the layout
+	// registered for it
+	initProject := func(version string, multiGroup bool) {
+		l, err := scaffold.GetLayout(version)
+		Expect(err).NotTo(HaveOccurred())
+		p := l.NewProject(scaffold.ProjectOptions{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version:    version,
+				Repo:       "example.com/project",
+				Domain:     "example.com",
+				MultiGroup: multiGroup,
+			}},
+			Boilerplate: project.Boilerplate{License: "apache2", Owner: "The Authors"},
+		})
+		Expect(p.Scaffold()).To(Succeed())
+	}
+
+	// expectFiles expects the files at paths to exist
+	expectFiles := func(paths ...string) {
+		for _, path := range paths {
+			exists, err := afero.Exists(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue(), path)
+		}
+	}
+
+	// expectNoFiles expects the files at paths not to exist
+	expectNoFiles := func(paths ...string) {
+		for _, path := range paths {
+			exists, err := afero.Exists(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse(), path)
+		}
+	}
+
+	It("should run init, create api and create webhook without touching the disk", func() {
+		p := &scaffold.V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Repo:    "example.com/project",
+				Domain:  "example.com",
+			}},
+			Boilerplate: project.Boilerplate{License: "apache2", Owner: "The Authors"},
+		}
+		Expect(p.Scaffold()).To(Succeed())
+
+		r := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(r.Validate()).To(Succeed())
+		api := &scaffold.API{Resource: r, DoResource: true, DoController: true}
+		Expect(api.Validate()).To(Succeed())
+		Expect(api.Scaffold()).To(Succeed())
+
+		wh := &scaffold.Webhook{Resource: r, Defaulting: true, Validating: true}
+		Expect(wh.Validate()).To(Succeed())
+		Expect(wh.Scaffold()).To(Succeed())
+
+		for _, path := range []string{
+			"PROJECT",
+			"main.go",
+			filepath.Join("api", "v1", "frigate_types.go"),
+			filepath.Join("api", "v1", "frigate_webhook.go"),
+			filepath.Join("controllers", "frigate_controller.go"),
+			filepath.Join("config", "crd", "kustomization.yaml"),
+		} {
+			exists, err := afero.Exists(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue(), path)
+
+			_, err = os.Stat(path)
+			Expect(os.IsNotExist(err)).To(BeTrue(), path)
+		}
+
+		b, err := afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("(&shipv1.Frigate{}).SetupWebhookWithManager(mgr)"))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("namespace: project-system"))
+
+		b, err = afero.ReadFile(filesystem.Fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("kind: Frigate"))
+	})
+
+	It("should put the APIs and controllers of each group in their own packages in multi group projects", func() {
+		initProject(project.Version2, true)
+
+		for _, r := range []*resource.Resource{
+			{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true},
+			{Group: "sea", Version: "v1", Kind: "Kraken", Namespaced: true},
+		} {
+			Expect(r.Validate()).To(Succeed())
+			api := &scaffold.API{Resource: r, DoResource: true, DoController: true}
+			Expect(api.Validate()).To(Succeed())
+			Expect(api.Scaffold()).To(Succeed())
+		}
+
+		wh := &scaffold.Webhook{Resource: &resource.Resource{Group: "sea", Version: "v1", Kind: "Kraken"}, Defaulting: true}
+		Expect(wh.Validate()).To(Succeed())
+		Expect(wh.Scaffold()).To(Succeed())
+
+		expectFiles(
+			filepath.Join("api", "ship", "v1", "frigate_types.go"),
+			filepath.Join("api", "ship", "v1", "groupversion_info.go"),
+			filepath.Join("api", "sea", "v1", "kraken_types.go"),
+			filepath.Join("api", "sea", "v1", "kraken_webhook.go"),
+			filepath.Join("controllers", "ship", "frigate_controller.go"),
+			filepath.Join("controllers", "ship", "suite_test.go"),
+			filepath.Join("controllers", "sea", "kraken_controller.go"),
+			filepath.Join("controllers", "sea", "suite_test.go"),
+		)
+
+		b, err := afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`seav1 "example.com/project/api/sea/v1"`))
+		Expect(string(b)).To(ContainSubstring(`shipcontrollers "example.com/project/controllers/ship"`))
+		Expect(string(b)).To(ContainSubstring("(&seacontrollers.KrakenReconciler{"))
+		Expect(string(b)).To(ContainSubstring("(&seav1.Kraken{}).SetupWebhookWithManager(mgr)"))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("controllers", "ship", "frigate_controller.go"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`shipv1 "example.com/project/api/ship/v1"`))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("controllers", "sea", "suite_test.go"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`filepath.Join("..", "..", "config", "crd", "bases")`))
+		Expect(string(b)).To(ContainSubstring("err = seav1.AddToScheme(scheme.Scheme)"))
+		Expect(string(b)).NotTo(ContainSubstring("shipv1"))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("config", "crd", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("- bases/ship.example.com_frigates.yaml\n- bases/sea.example.com_krakens.yaml\n"))
+	})
+
+	It("should refuse a second group without the multi group layout", func() {
+		initProject(project.Version2, false)
+
+		r := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(r.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: r, DoResource: true}).Scaffold()).To(Succeed())
+
+		r = &resource.Resource{Group: "sea", Version: "v1", Kind: "Kraken", Namespaced: true}
+		Expect(r.Validate()).To(Succeed())
+		err := (&scaffold.API{Resource: r, DoResource: true}).Scaffold()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("set 'multigroup: true' in PROJECT"))
+	})
+
+	It("should refuse to init over a Makefile and merge it on rescaffold", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "Makefile", []byte("all:\n"), 0600)).To(Succeed())
+		err := (&scaffold.V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Repo:    "example.com/project",
+				Domain:  "example.com",
+			}},
+			Boilerplate: project.Boilerplate{License: "apache2", Owner: "The Authors"},
+		}).Scaffold()
+		Expect(scaffold.IsAlreadyExists(err)).To(BeTrue(), "%v", err)
+
+		// init is not transactional, the commands run it in a transaction
+		filesystem.Fs = afero.NewMemMapFs()
+		initProject(project.Version2, false)
+		expectFiles(filepath.Join(".kubebuilder", "base", "Makefile"))
+
+		b, err := afero.ReadFile(filesystem.Fs, "Makefile")
+		Expect(err).NotTo(HaveOccurred())
+		edited := string(b) + "\n# deploy to the staging cluster\nstaging: deploy\n"
+		Expect(afero.WriteFile(filesystem.Fs, "Makefile", []byte(edited), 0600)).To(Succeed())
+
+		conflicts, err := (&scaffold.V2Project{}).Rescaffold()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+		b, err = afero.ReadFile(filesystem.Fs, "Makefile")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(edited))
+	})
+
+	It("should rescaffold with the image given to init", func() {
+		p := &scaffold.V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Repo:    "example.com/project",
+				Domain:  "example.com",
+			}},
+			Boilerplate: project.Boilerplate{License: "apache2", Owner: "The Authors"},
+			Image:       "example.com/frigate:v1",
+		}
+		Expect(p.Scaffold()).To(Succeed())
+		b, err := afero.ReadFile(filesystem.Fs, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("image: example.com/frigate:v1"))
+
+		conflicts, err := (&scaffold.V2Project{}).Rescaffold()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+		for _, path := range []string{"Makefile", filepath.Join("config", "manager", "manager.yaml")} {
+			b, err := afero.ReadFile(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(ContainSubstring("example.com/frigate:v1"), path)
+			Expect(string(b)).NotTo(ContainSubstring("controller:latest"), path)
+		}
+	})
+
+	It("should delete an API and its wiring", func() {
+		initProject(project.Version2, false)
+		mainGo, err := afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer", Namespaced: true}
+		Expect(destroyer.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: destroyer, DoResource: true, DoController: true}).Scaffold()).To(Succeed())
+
+		edited := []string{
+			"PROJECT",
+			"main.go",
+			filepath.Join("controllers", "suite_test.go"),
+			filepath.Join("config", "crd", "kustomization.yaml"),
+		}
+		before := map[string]string{}
+		for _, path := range edited {
+			b, err := afero.ReadFile(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			before[path] = string(b)
+		}
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(frigate.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: frigate, DoResource: true, DoController: true}).Scaffold()).To(Succeed())
+		Expect((&scaffold.Webhook{Resource: frigate, Defaulting: true}).Scaffold()).To(Succeed())
+
+		Expect((&scaffold.API{Resource: frigate}).Delete()).To(Succeed())
+		for _, path := range edited {
+			b, err := afero.ReadFile(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal(before[path]), path)
+		}
+		expectNoFiles(
+			filepath.Join("api", "v1", "frigate_types.go"),
+			filepath.Join("api", "v1", "frigate_webhook.go"),
+			filepath.Join("controllers", "frigate_controller.go"),
+			filepath.Join("config", "samples", "ship_v1_frigate.yaml"),
+			filepath.Join("config", "crd", "patches", "webhook_in_frigates.yaml"),
+		)
+		expectFiles(filepath.Join("api", "v1", "groupversion_info.go"))
+
+		// the last resource of the group version takes the group version with it
+		Expect((&scaffold.API{Resource: destroyer}).Delete()).To(Succeed())
+		b, err := afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(string(mainGo)))
+		expectNoFiles(
+			filepath.Join("api", "v1", "groupversion_info.go"),
+			filepath.Join("controllers", "suite_test.go"),
+		)
+
+		err = (&scaffold.API{Resource: destroyer}).Delete()
+		Expect(err).To(MatchError("resource ship/v1, Kind=Destroyer is not in the project"))
+	})
+
+	It("should edit the kustomization files of config/default, rbac and webhook for plugins", func() {
+		initProject(project.Version2, false)
+
+		listItem := func(path, list, item string, commented bool) plugin.Edit {
+			return plugin.Edit{Path: path, ListItem: &plugin.ListItem{List: list, Item: item, Commented: commented}}
+		}
+		conflicts, err := scaffold.ApplyPlugin(&plugin.Response{
+			APIVersion: plugin.APIVersion,
+			Edits: []plugin.Edit{
+				// the commented out base and patch of the webhook are enabled
+				listItem("config/default/kustomization.yaml", "bases", "../webhook", false),
+				listItem("config/default/kustomization.yaml", "bases", "../prometheus", true),
+				listItem("config/default/kustomization.yaml", "patches", "manager_webhook_patch.yaml", false),
+				listItem("config/default/kustomization.yaml", "vars",
+					"name: METRICS_SERVICE\nobjref:\n  kind: Service\n  version: v1\n  name: metrics", false),
+				listItem("config/rbac/kustomization.yaml", "resources", "frigate_editor_role.yaml", false),
+				listItem("config/rbac/kustomization.yaml", "resources", "role.yaml", false),
+				listItem("config/webhook/kustomization.yaml", "resources", "certificate.yaml", false),
+				listItem("config/webhook/kustomization.yaml", "configurations", "kustomizeconfig.yaml", false),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+
+		b, err := afero.ReadFile(filesystem.Fs, filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`bases:
+- ../crd
+- ../rbac
+- ../manager
+# [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in crd/kustomization.yaml
+- ../webhook
+# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'. 'WEBHOOK' components are required.
+#- ../certmanager
+#- ../prometheus
+`))
+		Expect(string(b)).To(ContainSubstring(`
+- manager_webhook_patch.yaml
+`))
+		Expect(string(b)).To(ContainSubstring(`vars:
+- name: METRICS_SERVICE
+  objref:
+    kind: Service
+    version: v1
+    name: metrics
+# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER' prefix.
+#- name: CERTIFICATE_NAMESPACE # namespace of the certificate CR
+`))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("config", "rbac", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`resources:
+- role.yaml
+- role_binding.yaml
+- leader_election_role.yaml
+- leader_election_role_binding.yaml
+# Comment the following 3 lines if you want to disable
+# the auth proxy (https://github.com/brancz/kube-rbac-proxy)
+# which protects your /metrics endpoint.
+- auth_proxy_service.yaml
+- auth_proxy_role.yaml
+- auth_proxy_role_binding.yaml
+- frigate_editor_role.yaml
+`))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("config", "webhook", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`resources:
+- manifests.yaml
+- service.yaml
+- certificate.yaml
+
+configurations:
+- kustomizeconfig.yaml
+`))
+	})
+
+	It("should rename the kind and the version of an API", func() {
+		initProject(project.Version2, false)
+
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer", Namespaced: true}
+		Expect(destroyer.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: destroyer, DoResource: true, DoController: true}).Scaffold()).To(Succeed())
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(frigate.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: frigate, DoResource: true, DoController: true}).Scaffold()).To(Succeed())
+		Expect((&scaffold.Webhook{Resource: frigate, Defaulting: true}).Scaffold()).To(Succeed())
+
+		rn := &scaffold.Rename{From: frigate, To: &resource.Resource{Kind: "Corvette"}}
+		Expect(rn.Rename()).To(Succeed())
+		Expect(rn.Moved).To(HaveKeyWithValue(
+			filepath.Join("api", "v1", "corvette_types.go"), filepath.Join("api", "v1", "frigate_types.go")))
+		for path, contents := range map[string][]string{
+			filepath.Join("api", "v1", "corvette_types.go"):             {"type Corvette struct", "type CorvetteList struct"},
+			filepath.Join("api", "v1", "corvette_webhook.go"):           {"/mutate-ship-example-com-v1-corvette", "func (r *Corvette) Default()"},
+			filepath.Join("controllers", "corvette_controller.go"):      {"resources=corvettes,", "type CorvetteReconciler struct"},
+			filepath.Join("config", "samples", "ship_v1_corvette.yaml"): {"kind: Corvette"},
+			filepath.Join("config", "crd", "kustomization.yaml"):        {"patches/webhook_in_corvettes.yaml"},
+			"main.go": {"controllers.CorvetteReconciler{", "(&shipv1.Corvette{}).SetupWebhookWithManager"},
+			"PROJECT": {"kind: Corvette", "plural: corvettes"},
+		} {
+			b, err := afero.ReadFile(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred(), path)
+			for _, content := range contents {
+				Expect(string(b)).To(ContainSubstring(content), path)
+			}
+			Expect(string(b)).NotTo(ContainSubstring("Frigate"), path)
+		}
+		expectNoFiles(filepath.Join("api", "v1", "frigate_types.go"))
+
+		// the new version is scaffolded and wired, the old one stays for the
+		// other kind
+		corvette := &resource.Resource{Group: "ship", Version: "v1", Kind: "Corvette"}
+		Expect((&scaffold.Rename{From: corvette, To: &resource.Resource{Version: "v2"}}).Rename()).To(Succeed())
+		b, err := afero.ReadFile(filesystem.Fs, filepath.Join("api", "v2", "corvette_types.go"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("package v2"))
+		for _, path := range []string{"main.go", filepath.Join("controllers", "suite_test.go")} {
+			b, err := afero.ReadFile(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(ContainSubstring("shipv1.AddToScheme"), path)
+			Expect(string(b)).To(ContainSubstring("shipv2.AddToScheme"), path)
+		}
+		expectFiles(filepath.Join("api", "v2", "groupversion_info.go"))
+
+		err = (&scaffold.Rename{From: corvette, To: &resource.Resource{Kind: "Sloop"}}).Rename()
+		Expect(err).To(MatchError("resource ship/v1, Kind=Corvette is not in the project"))
+	})
+
+	It("should record the options of the resources and regenerate the project from them", func() {
+		initProject(project.Version2, false)
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(frigate.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: frigate, DoResource: true, DoController: true}).Scaffold()).To(Succeed())
+		Expect((&scaffold.Webhook{Resource: frigate, Validating: true}).Scaffold()).To(Succeed())
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer", Namespaced: false}
+		Expect(destroyer.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: destroyer, DoResource: true}).Scaffold()).To(Succeed())
+		deployment := &resource.Resource{Group: "apps", Version: "v1", Kind: "Deployment", Namespaced: true}
+		Expect(deployment.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: deployment, DoController: true}).Scaffold()).To(Succeed())
+
+		pf, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pf.Resources).To(Equal([]input.Resource{
+			{
+				Group: "ship", Version: "v1", Kind: "Frigate", Plural: "frigates",
+				API: &input.ResourceAPI{Namespaced: true}, Controller: true,
+				Webhooks: &input.ResourceWebhooks{Validation: true},
+			},
+			{Group: "ship", Version: "v1", Kind: "Destroyer", Plural: "destroyers", API: &input.ResourceAPI{}},
+			{Group: "apps", Version: "v1", Kind: "Deployment", Plural: "deployments", Core: true, Controller: true},
+		}))
+
+		Expect((&scaffold.Regenerate{Output: "regenerated"}).Regenerate()).To(Succeed())
+		regenerated := afero.NewBasePathFs(filesystem.Fs, "regenerated")
+		var paths []string
+		err = afero.Walk(filesystem.Fs, ".", func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == "regenerated" {
+				return filepath.SkipDir
+			}
+			if !info.IsDir() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		expectFiles(filepath.Join("controllers", "deployment_controller.go"))
+		for _, path := range paths {
+			b, err := afero.ReadFile(filesystem.Fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			regeneratedB, err := afero.ReadFile(regenerated, path)
+			Expect(err).NotTo(HaveOccurred(), path)
+			Expect(string(regeneratedB)).To(Equal(string(b)), path)
+		}
+
+		err = (&scaffold.Regenerate{Output: "regenerated"}).Regenerate()
+		Expect(err).To(MatchError("regenerated is not empty"))
+	})
+
+	It("should migrate a version 1 project to the v2 layout", func() {
+		initProject(project.Version1, false)
+
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		Expect(frigate.Validate()).To(Succeed())
+		Expect((&scaffold.API{Resource: frigate, DoResource: true, DoController: true}).Scaffold()).To(Succeed())
+
+		m := &scaffold.Migrate{}
+		Expect(m.Migrate()).To(Succeed())
+		Expect(m.Unconverted).To(BeEmpty())
+		Expect(m.Moved).To(HaveKeyWithValue(
+			filepath.Join("controllers", "frigate_controller.go"),
+			filepath.Join("pkg", "controller", "frigate", "frigate_controller.go")))
+
+		expectFiles(
+			"go.mod",
+			"main.go",
+			filepath.Join("api", "v1", "frigate_types.go"),
+			filepath.Join("api", "v1", "groupversion_info.go"),
+			filepath.Join("controllers", "frigate_controller.go"),
+			filepath.Join("controllers", "suite_test.go"),
+		)
+		expectNoFiles(
+			"Gopkg.toml",
+			filepath.Join("cmd", "manager", "main.go"),
+			filepath.Join("pkg", "apis", "ship", "v1", "frigate_types.go"),
+			filepath.Join("pkg", "controller", "frigate", "frigate_controller.go"),
+		)
+
+		b, err := afero.ReadFile(filesystem.Fs, filepath.Join("controllers", "frigate_controller.go"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("package controllers"))
+		Expect(string(b)).To(ContainSubstring(`shipv1 "example.com/project/api/v1"`))
+		Expect(string(b)).To(ContainSubstring("func (*FrigateReconciler) SetupWithManager(mgr manager.Manager) error"))
+
+		b, err = afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("_ = shipv1.AddToScheme(scheme)"))
+		Expect(string(b)).To(ContainSubstring("(&controllers.FrigateReconciler{}).SetupWithManager(mgr)"))
+
+		pf, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pf.Version).To(Equal(project.Version2))
+		Expect(pf.Resources).To(Equal([]input.Resource{{
+			Group: "ship", Version: "v1", Kind: "Frigate", Plural: "frigates",
+			API: &input.ResourceAPI{Namespaced: true}, Controller: true,
+		}}))
+
+		Expect(m.Migrate()).To(MatchError("kubebuilder alpha migrate is not supported for project version 2"))
+	})
+
+	It("should write the go.mod of a version 1 project from Gopkg.lock", func() {
+		initProject(project.Version1, false)
+
+		g := &scaffold.GoModFromDep{}
+		Expect(g.Convert()).To(MatchError("the project has no Gopkg.lock, run dep ensure to lock the dependencies first"))
+
+		Expect(afero.WriteFile(filesystem.Fs, "Gopkg.lock", []byte(`[[projects]]
+  name = "gopkg.in/yaml.v2"
+  packages = ["."]
+  revision = "5420a8b6744d3b0345ab293f6fcba19c978f1183"
+  version = "v2.2.1"
+
+[[projects]]
+  digest = "1:4cb5c8f9d5a33d7bbb2e4ff1d0b1f6c8b2f9c6a9f8e3e4d1f2a3b4c5d6e7f8a9"
+  name = "github.com/evanphx/json-patch"
+  packages = ["."]
+  revision = "72bf35d0ff611848c1dc9df0f976c81192392fa5"
+  version = "v4.1.0"
+
+[[projects]]
+  branch = "master"
+  name = "golang.org/x/net"
+  packages = [
+    "context",
+    "http2",
+  ]
+  revision = "161cd47e91fd58ac17490ef4d742dc98bb4cf60e"
+
+[[projects]]
+  name = "gopkg.in/fsnotify.v1"
+  packages = ["."]
+  revision = "c2828203cd70a50dcccfb2761f8b1f8ceef9a8e9"
+  source = "https://github.com/fsnotify/fsnotify.git"
+  version = "v1.4.7"
+
+[[projects]]
+  name = "k8s.io/client-go"
+  packages = ["rest"]
+  revision = "e64494209f554a6723674bd494d69445fb76a1d4"
+  version = "kubernetes-1.13.1"
+
+[solve-meta]
+  analyzer-name = "dep"
+  analyzer-version = 1
+`), 0600)).To(Succeed())
+		// the projects locked on a revision only need a version in Gopkg.toml
+		Expect(g.Convert()).To(MatchError("error converting Gopkg.lock: the pseudo-versions of the projects " +
+			"locked on a revision only cannot be known without network access, add a [[constraint]] or an " +
+			"[[override]] with a version to Gopkg.toml for golang.org/x/net, k8s.io/client-go"))
+
+		b, err := afero.ReadFile(filesystem.Fs, "Gopkg.toml")
+		Expect(err).NotTo(HaveOccurred())
+		b = append(b, []byte(`
+[[constraint]]
+  name = "golang.org/x/net"
+  version = "=v0.0.0-20190311183353-d8887717615a"
+
+[[constraint]]
+  name = "github.com/evanphx/json-patch"
+  version = "^4.1.0"
+
+[[override]]
+  name = "k8s.io/client-go"
+  version = "v10.0.0"
+
+[[override]]
+  name = "gopkg.in/yaml.v2"
+  version = "=v2.2.2"
+`)...)
+		Expect(afero.WriteFile(filesystem.Fs, "Gopkg.toml", b, 0600)).To(Succeed())
+
+		Expect(g.Convert()).To(Succeed())
+		b, err = afero.ReadFile(filesystem.Fs, "go.mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`module example.com/project
+
+go 1.12
+
+require (
+	github.com/evanphx/json-patch v4.1.0+incompatible
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a
+	gopkg.in/fsnotify.v1 v1.4.7
+	gopkg.in/yaml.v2 v2.2.1
+	k8s.io/client-go v10.0.0+incompatible
+)
+
+replace (
+	gopkg.in/fsnotify.v1 => github.com/fsnotify/fsnotify v1.4.7
+	gopkg.in/yaml.v2 => gopkg.in/yaml.v2 v2.2.2
+)
+`))
+		expectFiles("Gopkg.lock")
+
+		Expect((&scaffold.GoModFromDep{}).Convert()).To(MatchError("go.mod already exists"))
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+// GoModFromDep writes the go.mod file of a project managed with dep from its
+// Gopkg.lock, without network access: the projects locked by dep are
+// required at their locked versions, and the sources of the projects and the
+// versions of the overrides and the exact constraints of Gopkg.toml which
+// differ from the locked ones become replace directives, so the project
+// builds with the dependencies dep resolved.
+type GoModFromDep struct{}
+
+// Convert writes go.mod, it fails if the project has no Gopkg.lock or
+// already has a go.mod. The files of dep are kept. A project locked on a
+// revision only is required at the version of its constraint or override in
+// Gopkg.toml, Convert fails if it has none since the pseudo-version of the
+// revision cannot be known without fetching it.
+func (g *GoModFromDep) Convert() error {
+	if _, err := LoadProjectFile("PROJECT"); err != nil {
+		return err
+	}
+
+	b, err := afero.ReadFile(filesystem.Fs, "Gopkg.lock")
+	if os.IsNotExist(err) {
+		return fmt.Errorf("the project has no Gopkg.lock, run dep ensure to lock the dependencies first")
+	}
+	if err != nil {
+		return err
+	}
+	lock, err := project.ParseGopkg(b)
+	if err != nil {
+		return fmt.Errorf("error parsing Gopkg.lock: %v", err)
+	}
+
+	b, err = afero.ReadFile(filesystem.Fs, "Gopkg.toml")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	tables, err := project.ParseGopkg(b)
+	if err != nil {
+		return fmt.Errorf("error parsing Gopkg.toml: %v", err)
+	}
+	// the constraints and the overrides of the projects by name, an override
+	// wins over a constraint as it does for dep
+	rules := map[string]project.GopkgTable{}
+	for _, t := range tables {
+		name := t.Values["name"]
+		if name == "" || (t.Name != "constraint" && t.Name != "override") {
+			continue
+		}
+		if r, ok := rules[name]; !ok || r.Name != "override" {
+			rules[name] = t
+		}
+	}
+
+	var require, replace, revisions []string
+	for _, t := range lock {
+		name := t.Values["name"]
+		if t.Name != "projects" || name == "" {
+			continue
+		}
+		rule, hasRule := rules[name]
+		version, ok := depModuleVersion(name, t.Values["version"])
+		if !ok && hasRule {
+			version, ok = depModuleVersion(name, rule.Values["version"])
+		}
+		if !ok {
+			revisions = append(revisions, name)
+			continue
+		}
+		require = append(require, name+" "+version)
+
+		// dep forces the versions of the overrides and of the exact
+		// constraints, a replace directive is only needed when they differ
+		// from the locked version
+		replaced := version
+		if hasRule && (rule.Name == "override" ||
+			strings.HasPrefix(strings.TrimSpace(rule.Values["version"]), "=")) {
+			if v, ok := depModuleVersion(name, rule.Values["version"]); ok {
+				replaced = v
+			}
+		}
+		source := t.Values["source"]
+		if source == "" && hasRule {
+			source = rule.Values["source"]
+		}
+		if source != "" || replaced != version {
+			replace = append(replace, depReplace(name, source, replaced))
+		}
+	}
+	if len(revisions) > 0 {
+		return fmt.Errorf("error converting Gopkg.lock: the pseudo-versions of the projects locked on "+
+			"a revision only cannot be known without network access, add a [[constraint]] or an "+
+			"[[override]] with a version to Gopkg.toml for %s", strings.Join(revisions, ", "))
+	}
+
+	sort.Strings(require)
+	sort.Strings(replace)
+	return (&Scaffold{}).Execute(input.Options{}, &project.GoMod{Require: require, Replace: replace})
+}
+
+// depModuleVersion returns the module version of the version of a dep
+// project named name, see goModVersion. Major versions above 1 of modules
+// whose path has no major version are +incompatible.
+func depModuleVersion(name, version string) (string, bool) {
+	version, ok := goModVersion(version)
+	if !ok {
+		return "", false
+	}
+	major := strings.SplitN(version, ".", 2)[0]
+	if major != "v0" && major != "v1" && pathMajor(name) != major {
+		version += "+incompatible"
+	}
+	return version, true
+}
+
+// pathMajor returns the major version at the end of a module path, e.g. v2
+// for github.com/foo/bar/v2 or gopkg.in/yaml.v2, and an empty string if the
+// path has none.
+func pathMajor(name string) string {
+	sep := "/v"
+	if strings.HasPrefix(name, "gopkg.in/") {
+		sep = ".v"
+	}
+	i := strings.LastIndex(name, sep)
+	if i < 0 {
+		return ""
+	}
+	if _, err := strconv.Atoi(name[i+len(sep):]); err != nil {
+		return ""
+	}
+	return name[i+1:]
+}
+
+// depReplace returns the replace directive of the project name at version,
+// replaced by the module of the source of the project, e.g.
+// https://github.com/fsnotify/fsnotify.git, or by itself if source is empty.
+func depReplace(name, source, version string) string {
+	module := name
+	if source != "" {
+		module = strings.TrimSuffix(source, ".git")
+		if i := strings.Index(module, "://"); i >= 0 {
+			module = module[i+len("://"):]
+		}
+	}
+	return fmt.Sprintf("%s => %s %s", name, module, version)
+}
+
+// goModVersion returns the module version of the version of a dep
+// constraint, e.g. v1.2.0 for ^1.2, and false if it is not a version or a
+// lower bound of versions.
+func goModVersion(version string) (string, bool) {
+	version = strings.TrimSpace(strings.SplitN(version, ",", 2)[0])
+	version = strings.TrimLeft(version, "^~=> ")
+	version = strings.TrimPrefix(version, "v")
+	if version == "" {
+		return "", false
+	}
+	pre := ""
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version, pre = version[:i], version[i:]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return "", false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", false
+		}
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return "v" + strings.Join(parts, ".") + pre, true
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+)
+
+// UpdateHeaders replaces the license headers of the files of the project by
+// the boilerplate: the headers of the Go files of main.go, api/ and
+// controllers/, and the comment headers mentioning a copyright or a license
+// of the YAML files of config/. Generated Go files are left to their
+// generator.
+type UpdateHeaders struct {
+	// BoilerplatePath is the path of the boilerplate, hack/boilerplate.go.txt
+	// if empty
+	BoilerplatePath string
+
+	// Updated are the paths of the files whose header was updated, sorted
+	Updated []string
+}
+
+// generatedRegexp matches the comment of generated Go files
+var generatedRegexp = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// Update replaces the headers which differ from the boilerplate.
+func (u *UpdateHeaders) Update() error {
+	if u.BoilerplatePath == "" {
+		u.BoilerplatePath = filepath.Join("hack", "boilerplate.go.txt")
+	}
+	b, err := afero.ReadFile(filesystem.Fs, u.BoilerplatePath)
+	if err != nil {
+		return fmt.Errorf("error reading the boilerplate: %v", err)
+	}
+	boilerplate := strings.TrimSpace(string(b))
+
+	paths, err := headerFilePaths()
+	if err != nil {
+		return err
+	}
+	u.Updated = nil
+	for _, path := range paths {
+		before, err := afero.ReadFile(filesystem.Fs, path)
+		if err != nil {
+			return err
+		}
+		var after string
+		if filepath.Ext(path) == ".go" {
+			if generatedRegexp.Match(before) {
+				continue
+			}
+			after = replaceGoHeader(string(before), boilerplate)
+		} else {
+			after = replaceYAMLHeader(string(before), boilerplate)
+		}
+		if bytes.Equal(before, []byte(after)) {
+			continue
+		}
+		if err := afero.WriteFile(filesystem.Fs, path, []byte(after), os.ModePerm); err != nil {
+			return err
+		}
+		if err := manifest.Edited(path, before, []byte(after)); err != nil {
+			return err
+		}
+		u.Updated = append(u.Updated, path)
+	}
+	return nil
+}
+
+// headerFilePaths returns the paths of the files whose header is updated,
+// sorted.
+func headerFilePaths() ([]string, error) {
+	paths := []string{}
+	if _, err := filesystem.Fs.Stat("main.go"); err == nil {
+		paths = append(paths, "main.go")
+	}
+	for dir, exts := range map[string][]string{
+		"api":         {".go"},
+		"controllers": {".go"},
+		"config":      {".yaml", ".yml"},
+	} {
+		err := afero.Walk(filesystem.Fs, dir, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				// the directory does not exist, or the file is removed in
+				// an overlay
+				return nil
+			}
+			if err != nil || info.IsDir() {
+				return err
+			}
+			if strings.HasPrefix(info.Name(), "zz_generated.") {
+				return nil
+			}
+			for _, ext := range exts {
+				if filepath.Ext(path) == ext {
+					paths = append(paths, path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// replaceGoHeader replaces the header of the Go source src by boilerplate,
+// or adds it if src has no header.
+func replaceGoHeader(src, boilerplate string) string {
+	return withHeader(src[goHeaderEnd(src):], boilerplate)
+}
+
+// replaceYAMLHeader replaces the header of the YAML document src by
+// boilerplate as comment, src is unchanged if it has no header.
+func replaceYAMLHeader(src, boilerplate string) string {
+	end := yamlHeaderEnd(src)
+	if end == 0 {
+		return src
+	}
+	var lines []string
+	for _, line := range commentText(boilerplate) {
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "#")
+		} else {
+			lines = append(lines, "# "+line)
+		}
+	}
+	return withHeader(src[end:], strings.Join(lines, "\n"))
+}
+
+// withHeader returns the file content rest with header, separated by a
+// blank line.
+func withHeader(rest, header string) string {
+	rest = strings.TrimLeft(rest, "\n")
+	if header == "" {
+		return rest
+	}
+	return header + "\n\n" + rest
+}
+
+// goHeaderEnd returns the end of the header at the start of the Go source
+// src, 0 if it has none. The header is the first comment when it is followed
+// by a blank line, so the package comment and the build constraints are not
+// taken as header.
+func goHeaderEnd(src string) int {
+	var end int
+	switch {
+	case strings.HasPrefix(src, "/*"):
+		i := strings.Index(src, "*/")
+		if i < 0 {
+			return 0
+		}
+		end = i + len("*/")
+	default:
+		for pos := 0; strings.HasPrefix(src[pos:], "//"); pos = end + 1 {
+			if isDirective(src[pos:]) {
+				break
+			}
+			nl := strings.Index(src[pos:], "\n")
+			if nl < 0 {
+				return 0
+			}
+			end = pos + nl
+		}
+	}
+	if end == 0 || !strings.HasPrefix(src[end:], "\n\n") {
+		return 0
+	}
+	return end
+}
+
+// isDirective returns true if the line comment at the start of src is a
+// build constraint.
+func isDirective(src string) bool {
+	return strings.HasPrefix(src, "// +build") || strings.HasPrefix(src, "//go:build")
+}
+
+// yamlHeaderEnd returns the end of the header at the start of the YAML
+// document src, 0 if it has none. The header is the first comment lines when
+// they are followed by a blank line and mention a copyright or a license.
+func yamlHeaderEnd(src string) int {
+	var end int
+	for pos := 0; strings.HasPrefix(src[pos:], "#"); pos = end + 1 {
+		nl := strings.Index(src[pos:], "\n")
+		if nl < 0 {
+			return 0
+		}
+		end = pos + nl
+	}
+	if end == 0 || !strings.HasPrefix(src[end:], "\n\n") {
+		return 0
+	}
+	header := strings.ToLower(src[:end])
+	if !strings.Contains(header, "copyright") && !strings.Contains(header, "license") {
+		return 0
+	}
+	return end
+}
+
+// commentText returns the lines of the text of the Go comment c, either a
+// general comment or line comments.
+func commentText(c string) []string {
+	if c == "" {
+		return nil
+	}
+	if strings.HasPrefix(c, "/*") {
+		c = strings.TrimSuffix(strings.TrimPrefix(c, "/*"), "*/")
+		return strings.Split(strings.Trim(c, "\n"), "\n")
+	}
+	lines := strings.Split(c, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(strings.TrimPrefix(line, "//"), " ")
+	}
+	return lines
+}
@@ -0,0 +1,103 @@
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+var _ = Describe("UpdateHeaders", func() {
+	const oldHeader = `/*
+Copyright 2019 Old Owners.
+*/`
+	const newHeader = `/*
+Copyright 2019 New Owners.
+
+Licensed under the MIT License.
+*/`
+
+	var oldFs afero.Fs
+
+	BeforeEach(func() {
+		oldFs = filesystem.Fs
+		filesystem.Fs = afero.NewMemMapFs()
+	})
+
+	AfterEach(func() {
+		filesystem.Fs = oldFs
+	})
+
+	write := func(path, content string) {
+		Expect(afero.WriteFile(filesystem.Fs, path, []byte(content), 0600)).To(Succeed())
+	}
+	read := func(path string) string {
+		b, err := afero.ReadFile(filesystem.Fs, path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("should replace the headers by the boilerplate", func() {
+		write(filepath.Join("hack", "boilerplate.go.txt"), newHeader)
+		write("main.go", oldHeader+"\n\npackage main\n")
+		write(filepath.Join("api", "v1", "frigate_types.go"), oldHeader+"\n\n// +build tools\n\npackage v1\n")
+		// the package comment and build constraints are not headers
+		write(filepath.Join("api", "v1", "doc.go"), "// Package v1 is the v1 API\npackage v1\n")
+		write(filepath.Join("controllers", "suite_test.go"), "// +build e2e\n\npackage controllers\n")
+		write(filepath.Join("controllers", "frigate_controller.go"), newHeader+"\n\npackage controllers\n")
+		write(filepath.Join("config", "samples", "ship_v1_frigate.yaml"),
+			"# Copyright 2019 Old Owners.\n\napiVersion: ship.example.com/v1\n")
+		write(filepath.Join("config", "default", "kustomization.yaml"), "# Adds namespace to all resources.\nnamespace: system\n")
+		generated := oldHeader + "\n\n// Code generated by controller-gen. DO NOT EDIT.\n\npackage v1\n"
+		write(filepath.Join("api", "v1", "zz_generated.deepcopy.go"), generated)
+		write(filepath.Join("api", "v1", "generated.go"), generated)
+		write(filepath.Join("pkg", "other.go"), oldHeader+"\n\npackage pkg\n")
+
+		u := &scaffold.UpdateHeaders{}
+		Expect(u.Update()).To(Succeed())
+		Expect(u.Updated).To(Equal([]string{
+			filepath.Join("api", "v1", "doc.go"),
+			filepath.Join("api", "v1", "frigate_types.go"),
+			filepath.Join("config", "samples", "ship_v1_frigate.yaml"),
+			filepath.Join("controllers", "suite_test.go"),
+			"main.go",
+		}))
+
+		Expect(read("main.go")).To(Equal(newHeader + "\n\npackage main\n"))
+		Expect(read(filepath.Join("api", "v1", "frigate_types.go"))).To(Equal(newHeader + "\n\n// +build tools\n\npackage v1\n"))
+		Expect(read(filepath.Join("api", "v1", "doc.go"))).To(Equal(newHeader + "\n\n// Package v1 is the v1 API\npackage v1\n"))
+		Expect(read(filepath.Join("controllers", "suite_test.go"))).To(Equal(newHeader + "\n\n// +build e2e\n\npackage controllers\n"))
+		Expect(read(filepath.Join("config", "samples", "ship_v1_frigate.yaml"))).To(Equal(
+			"# Copyright 2019 New Owners.\n#\n# Licensed under the MIT License.\n\napiVersion: ship.example.com/v1\n"))
+		Expect(read(filepath.Join("config", "default", "kustomization.yaml"))).To(Equal(
+			"# Adds namespace to all resources.\nnamespace: system\n"))
+		Expect(read(filepath.Join("api", "v1", "zz_generated.deepcopy.go"))).To(Equal(generated))
+		Expect(read(filepath.Join("api", "v1", "generated.go"))).To(Equal(generated))
+		Expect(read(filepath.Join("pkg", "other.go"))).To(Equal(oldHeader + "\n\npackage pkg\n"))
+
+		// the headers are up to date
+		Expect(u.Update()).To(Succeed())
+		Expect(u.Updated).To(BeEmpty())
+	})
+
+	It("should support line comment boilerplates", func() {
+		write("boilerplate.txt", "// Copyright 2019 New Owners.\n// SPDX-License-Identifier: MIT\n")
+		write("main.go", "// Copyright 2019 Old Owners.\n\npackage main\n")
+		write(filepath.Join("config", "rbac", "role.yaml"), "# Copyright 2019 Old Owners.\n# License: MIT\n\nkind: Role\n")
+
+		u := &scaffold.UpdateHeaders{BoilerplatePath: "boilerplate.txt"}
+		Expect(u.Update()).To(Succeed())
+		Expect(read("main.go")).To(Equal("// Copyright 2019 New Owners.\n// SPDX-License-Identifier: MIT\n\npackage main\n"))
+		Expect(read(filepath.Join("config", "rbac", "role.yaml"))).To(Equal(
+			"# Copyright 2019 New Owners.\n# SPDX-License-Identifier: MIT\n\nkind: Role\n"))
+	})
+
+	It("should fail without boilerplate", func() {
+		write("main.go", oldHeader+"\n\npackage main\n")
+		Expect((&scaffold.UpdateHeaders{}).Update()).To(MatchError(ContainSubstring("error reading the boilerplate")))
+	})
+})
@@ -28,6 +28,11 @@ const (
 
 	// Overwrite truncates and overwrites the existing file
 	Overwrite
+
+	// Merge three-way merges the changes between the last rendered and the
+	// new rendered template into the existing file, writing conflict markers
+	// where they disagree with the changes made to the file
+	Merge
 )
 
 // Input is the input for scaffoldig a file
@@ -38,6 +43,10 @@ type Input struct {
 	// IfExistsAction determines what to do if the file exists
 	IfExistsAction IfExistsAction
 
+	// Mergeable keeps the rendered template as base of a later Merge of the
+	// file, e.g. by rescaffold. It is always kept by the Merge action.
+	Mergeable bool
+
 	// TemplateBody is the template body to execute
 	TemplateBody string
 
@@ -53,6 +62,10 @@ type Input struct {
 	// Domain is the domain for the APIs
 	Domain string
 
+	// MultiGroup is true if the APIs of the project are in directories per
+	// group, api/<group>/<version>
+	MultiGroup bool
+
 	// Repo is the go project package
 	Repo string
 
@@ -73,6 +86,17 @@ func (i *Input) SetDomain(d string) {
 	}
 }
 
+// MultiGroup allows the multi group layout to be set on an object
+type MultiGroup interface {
+	// SetMultiGroup sets whether the project has the multi group layout
+	SetMultiGroup(bool)
+}
+
+// SetMultiGroup sets whether the project has the multi group layout
+func (i *Input) SetMultiGroup(m bool) {
+	i.MultiGroup = m
+}
+
 // Repo allows a repo to be set on an object
 type Repo interface {
 	// SetRepo sets the repo
@@ -144,6 +168,34 @@ type File interface {
 	GetInput() (Input, error)
 }
 
+// TemplatePart is a part of a template composed of parts
+type TemplatePart struct {
+	// Name is the name of the part, which is appended to the template key of
+	// the file for the user provided templates
+	Name string
+
+	// Body is the built-in template body of the part
+	Body string
+
+	// Used is whether the part is in the template body of the file
+	Used bool
+}
+
+// TemplateParts is implemented by the files whose template body is composed
+// of parts chosen by their options, so user provided templates override each
+// part instead of the composed body
+type TemplateParts interface {
+	// TemplateParts returns all the parts of the template, in order
+	TemplateParts() []TemplatePart
+}
+
+// Raw is implemented by the files written with their content as is, without
+// template, user provided template or formatting of the imports
+type Raw interface {
+	// RawContent returns the content of the file
+	RawContent() []byte
+}
+
 // Validate validates input
 type Validate interface {
 	// Validate returns true if the template has valid values
@@ -162,24 +214,41 @@ type Options struct {
 // ProjectFile is deserialized into a PROJECT file
 type ProjectFile struct {
 	// Version is the project version - defaults to "1"
-	Version string `yaml:"version,omitempty"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
 
 	// Domain is the domain associated with the project and used for API groups
-	Domain string `yaml:"domain,omitempty"`
+	Domain string `yaml:"domain,omitempty" json:"domain,omitempty"`
 
 	// Repo is the go package name of the project root
-	Repo string `yaml:"repo,omitempty"`
+	Repo string `yaml:"repo,omitempty" json:"repo,omitempty"`
+
+	// TemplateDir is the directory with user provided templates overriding
+	// the built-in ones
+	TemplateDir string `yaml:"templates,omitempty" json:"templates,omitempty"`
+
+	// MultiGroup puts the APIs in directories per group, api/<group>/<version>,
+	// and their controllers in controllers/<group>, so the project can have
+	// more than one group. Only supported by project version 2.
+	MultiGroup bool `yaml:"multigroup,omitempty" json:"multigroup,omitempty"`
+
+	// Image is the controller manager image given to init, which rescaffold
+	// renders again, empty for controller:latest. Only recorded by project
+	// version 2.
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
 
 	// Resources tracks scaffolded resources in the project. This info is
 	// tracked only in project with version 2.
-	Resources []Resource `yaml:"resources,omitempty"`
+	Resources []Resource `yaml:"resources,omitempty" json:"resources,omitempty"`
 }
 
-// ResourceGroups returns unique groups of scaffolded resources in the project.
+// ResourceGroups returns unique groups of the resources scaffolded with API
+// types in the project.
 func (pf *ProjectFile) ResourceGroups() []string {
 	groupSet := map[string]struct{}{}
 	for _, r := range pf.Resources {
-		groupSet[r.Group] = struct{}{}
+		if r.API != nil {
+			groupSet[r.Group] = struct{}{}
+		}
 	}
 
 	groups := []string{}
@@ -191,7 +260,49 @@ func (pf *ProjectFile) ResourceGroups() []string {
 
 // Resource contains information about scaffolded resources.
 type Resource struct {
-	Group   string `yaml:"group,omitempty"`
-	Version string `yaml:"version,omitempty"`
-	Kind    string `yaml:"kind,omitempty"`
+	Group   string `yaml:"group,omitempty" json:"group,omitempty"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	Kind    string `yaml:"kind,omitempty" json:"kind,omitempty"`
+
+	// Plural is the plural name of the resource, as used in the name of its
+	// CRD and in the RBAC rules of its controller
+	Plural string `yaml:"plural,omitempty" json:"plural,omitempty"`
+
+	// API are the options of the scaffolded API types, nil if only a
+	// controller or webhooks were scaffolded for the resource
+	API *ResourceAPI `yaml:"api,omitempty" json:"api,omitempty"`
+
+	// Core is true if the types of the resource, without API, are the types
+	// of a Kubernetes API group in k8s.io/api
+	Core bool `yaml:"core,omitempty" json:"core,omitempty"`
+
+	// External is true if the types of the resource, without API, are neither
+	// in the project nor core types
+	External bool `yaml:"external,omitempty" json:"external,omitempty"`
+
+	// Controller is true if a controller was scaffolded for the resource
+	Controller bool `yaml:"controller,omitempty" json:"controller,omitempty"`
+
+	// Webhooks are the scaffolded webhooks of the resource, nil if none
+	Webhooks *ResourceWebhooks `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+}
+
+// ResourceAPI are the options the API types of a resource were scaffolded
+// with.
+type ResourceAPI struct {
+	// Namespaced is true for namespaced resources, false for cluster scoped
+	// ones
+	Namespaced bool `yaml:"namespaced,omitempty" json:"namespaced,omitempty"`
+}
+
+// ResourceWebhooks are the webhooks scaffolded for a resource.
+type ResourceWebhooks struct {
+	Defaulting bool `yaml:"defaulting,omitempty" json:"defaulting,omitempty"`
+	Validation bool `yaml:"validation,omitempty" json:"validation,omitempty"`
+	Conversion bool `yaml:"conversion,omitempty" json:"conversion,omitempty"`
+}
+
+// Is returns true if r is the resource of the group, version and kind.
+func (r *Resource) Is(group, version, kind string) bool {
+	return r.Group == group && r.Version == version && r.Kind == kind
 }
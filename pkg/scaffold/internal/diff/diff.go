@@ -0,0 +1,342 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff implements line based diffing of scaffolded files.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OpKind is the kind of a single diff operation
+type OpKind int
+
+const (
+	// Equal means the line is present in both inputs
+	Equal OpKind = iota
+
+	// Delete means the line is only present in the old input
+	Delete
+
+	// Insert means the line is only present in the new input
+	Insert
+)
+
+// Op is a single line of a diff
+type Op struct {
+	Kind OpKind
+
+	// Line is the line content including its trailing newline, if any
+	Line string
+
+	// A and B are the indexes of the line in the old and new input. A is -1
+	// for inserted lines and B is -1 for deleted lines.
+	A, B int
+}
+
+// SplitLines splits s into lines, keeping the trailing newline on each line.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Lines returns the operations turning a into b, based on their longest
+// common subsequence.
+func Lines(a, b []string) []Op {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]Op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Op{Kind: Equal, Line: a[i], A: i, B: j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, Op{Kind: Delete, Line: a[i], A: i, B: -1})
+			i++
+		default:
+			ops = append(ops, Op{Kind: Insert, Line: b[j], A: -1, B: j})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, Op{Kind: Delete, Line: a[i], A: i, B: -1})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, Op{Kind: Insert, Line: b[j], A: -1, B: j})
+	}
+	return ops
+}
+
+// Unified writes the unified diff between old and new to w. A nil old is
+// shown as a file creation and a nil new as a file deletion.
+func Unified(w io.Writer, path string, old, new []byte, context int) error {
+	oldName, newName := "a/"+path, "b/"+path
+	if old == nil {
+		oldName = "/dev/null"
+	}
+	if new == nil {
+		newName = "/dev/null"
+	}
+
+	ops := Lines(SplitLines(string(old)), SplitLines(string(new)))
+	hunks := hunks(ops, context)
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName); err != nil {
+		return err
+	}
+	for _, h := range hunks {
+		if err := h.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hunk is a run of operations with surrounding context lines
+type hunk struct {
+	ops []Op
+
+	// aStart and bStart are the zero based line the hunk starts at in the
+	// old and new input
+	aStart, bStart int
+}
+
+// hunks groups the changes in ops into hunks with up to context unchanged
+// lines around them.
+func hunks(ops []Op, context int) []hunk {
+	var result []hunk
+	start := -1
+	lastChange := -1
+	for i, op := range ops {
+		if op.Kind != Equal {
+			if start == -1 || i-lastChange > 2*context {
+				if start != -1 {
+					result = append(result, newHunk(ops, start, lastChange+context+1))
+				}
+				start = i - context
+				if start < 0 {
+					start = 0
+				}
+			}
+			lastChange = i
+		}
+	}
+	if start != -1 {
+		result = append(result, newHunk(ops, start, lastChange+context+1))
+	}
+	return result
+}
+
+func newHunk(ops []Op, start, end int) hunk {
+	if end > len(ops) {
+		end = len(ops)
+	}
+	h := hunk{ops: ops[start:end]}
+	for _, op := range ops[:start] {
+		if op.Kind != Insert {
+			h.aStart++
+		}
+		if op.Kind != Delete {
+			h.bStart++
+		}
+	}
+	return h
+}
+
+func (h hunk) write(w io.Writer) error {
+	aLen, bLen := 0, 0
+	for _, op := range h.ops {
+		if op.Kind != Insert {
+			aLen++
+		}
+		if op.Kind != Delete {
+			bLen++
+		}
+	}
+	if _, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(h.aStart, aLen), hunkRange(h.bStart, bLen)); err != nil {
+		return err
+	}
+
+	for _, op := range h.ops {
+		prefix := " "
+		switch op.Kind {
+		case Delete:
+			prefix = "-"
+		case Insert:
+			prefix = "+"
+		}
+		line := prefix + op.Line
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n\\ No newline at end of file\n"
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hunkRange formats the line range of a hunk header, the way diff -u does.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, length)
+	}
+}
+
+const (
+	conflictStart = "<<<<<<< "
+	conflictSep   = "=======\n"
+	conflictEnd   = ">>>>>>> "
+)
+
+// Merge does a three-way merge of the changes from base to ours and from base
+// to theirs. Conflicting changes are written between conflict markers labeled
+// with oursLabel and theirsLabel. It returns the merged lines and the number of
+// conflicts. A nil base merges ours and theirs without a common ancestor, so
+// every difference between them is a conflict.
+func Merge(base, ours, theirs []string, oursLabel, theirsLabel string) ([]string, int) {
+	if base == nil {
+		// use the lines ours and theirs have in common as ancestor, and make
+		// sure lines only added on one side are not taken silently
+		for _, op := range Lines(ours, theirs) {
+			if op.Kind == Equal {
+				base = append(base, op.Line)
+			}
+		}
+		return merge(base, ours, theirs, oursLabel, theirsLabel, true)
+	}
+	return merge(base, ours, theirs, oursLabel, theirsLabel, false)
+}
+
+func merge(base, ours, theirs []string, oursLabel, theirsLabel string, conflictOnAdd bool) ([]string, int) {
+	// matched base lines, mapped to their index in ours and theirs
+	inOurs := matches(base, ours)
+	inTheirs := matches(base, theirs)
+
+	var merged []string
+	conflicts := 0
+	b, o, t := 0, 0, 0
+	for b <= len(base) {
+		// find the next base line that is unchanged on both sides
+		next := b
+		for ; next < len(base); next++ {
+			if inOurs[next] >= o && inTheirs[next] >= t {
+				break
+			}
+		}
+		oEnd, tEnd := len(ours), len(theirs)
+		if next < len(base) {
+			oEnd, tEnd = inOurs[next], inTheirs[next]
+		}
+
+		baseChunk, oursChunk, theirsChunk := base[b:next], ours[o:oEnd], theirs[t:tEnd]
+		switch {
+		case equal(oursChunk, theirsChunk):
+			merged = append(merged, oursChunk...)
+		case equal(baseChunk, oursChunk) && !(conflictOnAdd && len(baseChunk) == 0):
+			merged = append(merged, theirsChunk...)
+		case equal(baseChunk, theirsChunk) && !(conflictOnAdd && len(baseChunk) == 0):
+			merged = append(merged, oursChunk...)
+		default:
+			conflicts++
+			merged = append(merged, conflictStart+oursLabel+"\n")
+			merged = append(merged, terminated(oursChunk)...)
+			merged = append(merged, conflictSep)
+			merged = append(merged, terminated(theirsChunk)...)
+			merged = append(merged, conflictEnd+theirsLabel+"\n")
+		}
+
+		if next == len(base) {
+			break
+		}
+		merged = append(merged, base[next])
+		b, o, t = next+1, oEnd+1, tEnd+1
+	}
+	return merged, conflicts
+}
+
+// matches returns for every line of a the index of the matching line in b,
+// or -1 if the line was removed.
+func matches(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+	for _, op := range Lines(a, b) {
+		if op.Kind == Equal {
+			m[op.A] = op.B
+		}
+	}
+	return m
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// terminated makes sure the last line ends with a newline, so the conflict
+// markers start on their own line.
+func terminated(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	out := append([]string{}, lines...)
+	out[len(out)-1] += "\n"
+	return out
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type unifiedTest struct {
+	old, new []byte
+	expected string
+}
+
+func TestUnified(t *testing.T) {
+	tests := []unifiedTest{
+		{ // unchanged
+			old:      []byte("a\nb\n"),
+			new:      []byte("a\nb\n"),
+			expected: "",
+		},
+		{ // created
+			old: nil,
+			new: []byte("a\nb\n"),
+			expected: `--- /dev/null
++++ b/file
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{ // inserted in the middle with context
+			old: []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n"),
+			new: []byte("1\n2\n3\n4\nx\n5\n6\n7\n8\n9\n"),
+			expected: `--- a/file
++++ b/file
+@@ -2,6 +2,7 @@
+ 2
+ 3
+ 4
++x
+ 5
+ 6
+ 7
+`,
+		},
+		{ // two distant changes produce two hunks
+			old: []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"),
+			new: []byte("0\n2\n3\n4\n5\n6\n7\n8\n9\n11\n"),
+			expected: `--- a/file
++++ b/file
+@@ -1,4 +1,4 @@
+-1
++0
+ 2
+ 3
+ 4
+@@ -7,4 +7,4 @@
+ 7
+ 8
+ 9
+-10
++11
+`,
+		},
+		{ // missing trailing newline
+			old: []byte("a"),
+			new: []byte("b"),
+			expected: `--- a/file
++++ b/file
+@@ -1 +1 @@
+-a
+\ No newline at end of file
++b
+\ No newline at end of file
+`,
+		},
+	}
+
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		if err := Unified(out, "file", test.old, test.new, 3); err != nil {
+			t.Errorf("error %v", err)
+		}
+		if out.String() != test.expected {
+			t.Errorf("got: %s and wanted: %s", out.String(), test.expected)
+		}
+	}
+}
+
+type mergeTest struct {
+	base, ours, theirs string
+	noBase             bool
+	expected           string
+	conflicts          int
+}
+
+func TestMerge(t *testing.T) {
+	tests := []mergeTest{
+		{ // changes on both sides in different places
+			base:     "a\nb\nc\nd\ne\n",
+			ours:     "a\nB\nc\nd\ne\n",
+			theirs:   "a\nb\nc\nd\nE\n",
+			expected: "a\nB\nc\nd\nE\n",
+		},
+		{ // the same change on both sides
+			base:     "a\nb\nc\n",
+			ours:     "a\nx\nc\n",
+			theirs:   "a\nx\nc\n",
+			expected: "a\nx\nc\n",
+		},
+		{ // lines added and removed
+			base:     "a\nb\nc\nd\n",
+			ours:     "a\nb\nb2\nc\nd\n",
+			theirs:   "a\nb\nc\n",
+			expected: "a\nb\nb2\nc\n",
+		},
+		{ // conflicting changes
+			base:   "a\nb\nc\n",
+			ours:   "a\nours\nc\n",
+			theirs: "a\ntheirs\nc\n",
+			expected: `a
+<<<<<<< ours
+ours
+=======
+theirs
+>>>>>>> theirs
+c
+`,
+			conflicts: 1,
+		},
+		{ // conflicting changes without trailing newline
+			base:   "a\nb",
+			ours:   "a\nours",
+			theirs: "a\ntheirs",
+			expected: `a
+<<<<<<< ours
+ours
+=======
+theirs
+>>>>>>> theirs
+`,
+			conflicts: 1,
+		},
+		{ // without a base every difference conflicts
+			noBase: true,
+			ours:   "a\nours\nc\n",
+			theirs: "a\nc\n",
+			expected: `a
+<<<<<<< ours
+ours
+=======
+>>>>>>> theirs
+c
+`,
+			conflicts: 1,
+		},
+		{ // without a base equal files merge cleanly
+			noBase:   true,
+			ours:     "a\nb\n",
+			theirs:   "a\nb\n",
+			expected: "a\nb\n",
+		},
+	}
+
+	for _, test := range tests {
+		base := SplitLines(test.base)
+		if test.noBase {
+			base = nil
+		} else if base == nil {
+			base = []string{}
+		}
+		merged, conflicts := Merge(base, SplitLines(test.ours), SplitLines(test.theirs), "ours", "theirs")
+		if got := strings.Join(merged, ""); got != test.expected {
+			t.Errorf("got: %s and wanted: %s", got, test.expected)
+		}
+		if conflicts != test.conflicts {
+			t.Errorf("got %d conflicts and wanted %d", conflicts, test.conflicts)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+// DefaultVersion is the project version of new projects
+const DefaultVersion = project.Version2
+
+// ProjectOptions are the options for scaffolding a new project
+type ProjectOptions struct {
+	Project     project.Project
+	Boilerplate project.Boilerplate
+
+	// Image is the controller manager image, controller:latest if empty
+	Image string
+
+	// NamePrefix is the prefix of the names and namespace of the resources,
+	// the name of the project directory if empty
+	NamePrefix string
+
+	// DepArgs are the additional arguments of dep, only used by version 1
+	DepArgs []string
+
+	// DefinitelyEnsure is whether to run dep ensure, nil to ask the user,
+	// only used by version 1
+	DefinitelyEnsure *bool
+}
+
+// Layout is a scaffolding version, which is the version of the projects it
+// scaffolds. Each layout is a package registering itself with
+// RegisterLayout in its init function, e.g. pkg/scaffold/layout/v2, so a new
+// layout is added without changing the commands.
+type Layout struct {
+	// Version is the project version, e.g. "2"
+	Version string
+
+	// ProjectSchema are the fields of the PROJECT file of the version
+	ProjectSchema map[string]SchemaField
+
+	// MigrateFrom is the previous project version, whose PROJECT files are
+	// migrated to the version by MigrateProject
+	MigrateFrom string
+
+	// MigrateProject migrates a PROJECT file of the version MigrateFrom to
+	// the version, nil if the layout has no previous version
+	MigrateProject func(p *input.ProjectFile) error
+
+	// Migrate migrates the files of a project of the version MigrateFrom to
+	// the layout, nil if the layout does not support 'alpha migrate'
+	Migrate func(m *Migrate, p *input.ProjectFile) error
+
+	// NewProject returns the scaffolder of new projects
+	NewProject func(o ProjectOptions) ProjectScaffolder
+
+	// ScaffoldAPI scaffolds an API in a project of the version
+	ScaffoldAPI func(api *API, p *input.ProjectFile) error
+
+	// ScaffoldWebhook scaffolds the webhooks of a resource in a project of
+	// the version, nil if the layout does not support 'create webhook'
+	ScaffoldWebhook func(wh *Webhook, p *input.ProjectFile) error
+
+	// DeleteAPI removes an API scaffolded by ScaffoldAPI and ScaffoldWebhook
+	// from a project of the version, nil if the layout does not support
+	// 'delete api'
+	DeleteAPI func(api *API, p *input.ProjectFile) error
+
+	// RenameAPI renames a resource in a project of the version, nil if the
+	// layout does not support 'alpha rename'
+	RenameAPI func(rn *Rename, p *input.ProjectFile) error
+
+	// Regenerate scaffolds a project of the version again from its PROJECT
+	// file, nil if the layout does not support 'alpha regenerate'
+	Regenerate func(rg *Regenerate, p *input.ProjectFile) error
+
+	// Commands are the commands only available in projects of the version,
+	// in addition to the commands of all versions. Outside of a project the
+	// create commands of the default version are available.
+	Commands []LayoutCommand
+}
+
+// LayoutCommand is a command of a layout
+type LayoutCommand struct {
+	// Parent is the kubebuilder command the command is added to, "create"
+	// or "alpha", or empty for a command of kubebuilder itself
+	Parent string
+
+	// New returns the command
+	New func() *cobra.Command
+}
+
+// errNoLayout is returned when no layout is registered, i.e. by programs
+// using the package without importing the layouts
+var errNoLayout = errors.New("no project layout is registered, import the layouts, " +
+	`e.g. _ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v2"`)
+
+var (
+	layoutsMu sync.RWMutex
+	layouts   = map[string]*Layout{}
+)
+
+// RegisterLayout makes a layout available for its project version. It
+// panics if a layout is registered twice for the same version.
+func RegisterLayout(l *Layout) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	if l == nil || l.Version == "" {
+		panic("scaffold: RegisterLayout of a layout without version")
+	}
+	if _, dup := layouts[l.Version]; dup {
+		panic("scaffold: RegisterLayout called twice for version " + l.Version)
+	}
+	layouts[l.Version] = l
+}
+
+// RegisterLayoutCommands adds commands to the registered layout of the
+// project version, for commands which are not implemented by the package of
+// the layout. It panics if no layout is registered for the version.
+func RegisterLayoutCommands(version string, cmds ...LayoutCommand) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	l, found := layouts[version]
+	if !found {
+		panic("scaffold: RegisterLayoutCommands called for unregistered version " + version)
+	}
+	l.Commands = append(l.Commands, cmds...)
+}
+
+// GetLayout returns the layout of the project version.
+func GetLayout(version string) (*Layout, error) {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	if len(layouts) == 0 {
+		return nil, errNoLayout
+	}
+	l, found := layouts[version]
+	if !found {
+		return nil, fmt.Errorf("unsupported project version %q, supported versions are %s",
+			version, strings.Join(layoutVersions(), ", "))
+	}
+	return l, nil
+}
+
+// LayoutVersions returns the versions of the registered layouts, sorted.
+func LayoutVersions() []string {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	return layoutVersions()
+}
+
+func layoutVersions() []string {
+	versions := make([]string, 0, len(layouts))
+	for v := range layouts {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
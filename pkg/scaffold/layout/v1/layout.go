@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1 registers the layout of version 1 projects, which have the
+// types in pkg/apis, the controllers in pkg/controller and their
+// dependencies managed with dep. Its commands are implemented and registered
+// by kubebuilder.
+package v1
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+func init() {
+	scaffold.RegisterLayout(&scaffold.Layout{
+		Version:       project.Version1,
+		ProjectSchema: scaffold.ProjectSchemaV1,
+		NewProject: func(o scaffold.ProjectOptions) scaffold.ProjectScaffolder {
+			return &scaffold.V1Project{
+				Project:          o.Project,
+				Boilerplate:      o.Boilerplate,
+				Image:            o.Image,
+				NamePrefix:       o.NamePrefix,
+				DepArgs:          o.DepArgs,
+				DefinitelyEnsure: o.DefinitelyEnsure,
+			}
+		},
+		ScaffoldAPI: (*scaffold.API).ScaffoldV1,
+	})
+}
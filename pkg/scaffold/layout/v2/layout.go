@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v2 registers the layout of version 2 projects, which have the
+// types in api, the controllers in controllers and their dependencies
+// managed with go modules. Its commands are implemented and registered by
+// kubebuilder.
+package v2
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+func init() {
+	scaffold.RegisterLayout(&scaffold.Layout{
+		Version:        project.Version2,
+		ProjectSchema:  scaffold.ProjectSchemaV2,
+		MigrateFrom:    project.Version1,
+		MigrateProject: scaffold.MigrateProjectV2,
+		Migrate:        (*scaffold.Migrate).MigrateV2,
+		NewProject: func(o scaffold.ProjectOptions) scaffold.ProjectScaffolder {
+			return &scaffold.V2Project{
+				Project:     o.Project,
+				Boilerplate: o.Boilerplate,
+				Image:       o.Image,
+				NamePrefix:  o.NamePrefix,
+			}
+		},
+		ScaffoldAPI:     (*scaffold.API).ScaffoldV2,
+		ScaffoldWebhook: (*scaffold.Webhook).ScaffoldV2,
+		DeleteAPI:       (*scaffold.API).DeleteV2,
+		RenameAPI:       (*scaffold.Rename).RenameV2,
+		Regenerate:      (*scaffold.Regenerate).RegenerateV2,
+	})
+}
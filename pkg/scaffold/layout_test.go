@@ -0,0 +1,83 @@
+package scaffold_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+const projectV1 = `version: "1"
+domain: example.com
+repo: example.com/project
+`
+
+var _ = Describe("Layouts", func() {
+	var oldFs afero.Fs
+
+	BeforeEach(func() {
+		oldFs = filesystem.Fs
+		filesystem.Fs = afero.NewMemMapFs()
+	})
+
+	AfterEach(func() {
+		filesystem.Fs = oldFs
+	})
+
+	It("should register the built-in versions", func() {
+		Expect(scaffold.LayoutVersions()).To(Equal([]string{"1", "2"}))
+		l, err := scaffold.GetLayout(scaffold.DefaultVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.ScaffoldWebhook).NotTo(BeNil())
+	})
+
+	It("should refuse to register a version twice", func() {
+		Expect(func() { scaffold.RegisterLayout(&scaffold.Layout{Version: "2"}) }).To(Panic())
+	})
+
+	It("should fail clearly for unsupported project versions", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT", []byte("version: \"3\"\n"), 0600)).To(Succeed())
+		r := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+
+		err := (&scaffold.API{Resource: r, DoResource: true}).Scaffold()
+		Expect(err).To(MatchError(`invalid PROJECT: version: unsupported project version "3", supported versions are 1, 2`))
+
+		err = (&scaffold.Webhook{Resource: r, Defaulting: true}).Validate()
+		Expect(err).To(MatchError(`invalid PROJECT: version: unsupported project version "3", supported versions are 1, 2`))
+	})
+
+	It("should fail for webhooks of layouts without them", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT", []byte(projectV1), 0600)).To(Succeed())
+		r := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+
+		err := (&scaffold.Webhook{Resource: r, Defaulting: true}).Scaffold()
+		Expect(err).To(MatchError("kubebuilder create webhook is not supported for project version 1"))
+	})
+
+	It("should fail for deleting APIs of layouts without it", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT", []byte(projectV1), 0600)).To(Succeed())
+		r := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+
+		err := (&scaffold.API{Resource: r}).Delete()
+		Expect(err).To(MatchError("kubebuilder delete api is not supported for project version 1"))
+	})
+
+	It("should fail for renaming APIs of layouts without it", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT", []byte(projectV1), 0600)).To(Succeed())
+		from := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		to := &resource.Resource{Kind: "Corvette"}
+
+		err := (&scaffold.Rename{From: from, To: to}).Rename()
+		Expect(err).To(MatchError("kubebuilder alpha rename is not supported for project version 1"))
+	})
+
+	It("should fail for regenerating projects of layouts without it", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT", []byte(projectV1), 0600)).To(Succeed())
+
+		err := (&scaffold.Regenerate{Output: "regenerated"}).Regenerate()
+		Expect(err).To(MatchError("kubebuilder alpha regenerate is not supported for project version 1"))
+	})
+})
@@ -0,0 +1,238 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifest keeps track of the files written by the scaffolding, so
+// changes made to them afterwards can be detected.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+
+	"sigs.k8s.io/kubebuilder/cmd/version"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+// Path is the path of the manifest relative to the project root
+var Path = filepath.Join(".kubebuilder", "manifest.yaml")
+
+// File is a scaffolded file
+type File struct {
+	// Path is the path of the file relative to the project root
+	Path string `yaml:"path"`
+
+	// Template is the key of the template the file was rendered from, empty
+	// for files written without a template
+	Template string `yaml:"template,omitempty"`
+
+	// Version is the kubebuilder version that last wrote the file
+	Version string `yaml:"version"`
+
+	// SHA256 is the hash of the content last written
+	SHA256 string `yaml:"sha256"`
+}
+
+// Manifest lists the scaffolded files
+type Manifest struct {
+	Files []File `yaml:"files"`
+}
+
+// State is the state of a scaffolded file compared to its content last
+// written by the scaffolding
+type State string
+
+const (
+	// Pristine files have not been changed
+	Pristine State = "pristine"
+
+	// Modified files have been changed
+	Modified State = "modified"
+
+	// Deleted files have been removed
+	Deleted State = "deleted"
+)
+
+// Status is the state of a scaffolded file
+type Status struct {
+	File
+	State State
+}
+
+// Load reads the manifest, it is empty if the project has none.
+func Load() (*Manifest, error) {
+	m := &Manifest{}
+	exists, err := afero.Exists(filesystem.Fs, Path)
+	if err != nil || !exists {
+		return m, err
+	}
+	b, err := afero.ReadFile(filesystem.Fs, Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", Path, err)
+	}
+	return m, nil
+}
+
+// Save writes the manifest with the files sorted by path.
+func (m *Manifest) Save() error {
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("error marshalling manifest %v", err)
+	}
+	if err := filesystem.Fs.MkdirAll(filepath.Dir(Path), 0700); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(filesystem.Fs, Path, b, 0600); err != nil {
+		return fmt.Errorf("failed to save manifest at %s %v", Path, err)
+	}
+	return nil
+}
+
+// Get returns the entry of the file at path, or nil if it is not tracked.
+func (m *Manifest) Get(path string) *File {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for i := range m.Files {
+		if m.Files[i].Path == path {
+			return &m.Files[i]
+		}
+	}
+	return nil
+}
+
+// set records content as the last written content of the file at path.
+func (m *Manifest) set(path, template string, content []byte) {
+	f := m.Get(path)
+	if f == nil {
+		m.Files = append(m.Files, File{Path: filepath.ToSlash(filepath.Clean(path))})
+		f = &m.Files[len(m.Files)-1]
+	}
+	if template != "" {
+		f.Template = template
+	}
+	f.Version = version.GetVersion().KubeBuilderVersion
+	f.SHA256 = Hash(content)
+}
+
+// Status returns the state of all scaffolded files, sorted by path.
+func (m *Manifest) Status() ([]Status, error) {
+	var statuses []Status
+	for _, f := range m.Files {
+		s := Status{File: f, State: Pristine}
+		p := filepath.FromSlash(f.Path)
+		exists, err := afero.Exists(filesystem.Fs, p)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			s.State = Deleted
+		} else {
+			b, err := afero.ReadFile(filesystem.Fs, p)
+			if err != nil {
+				return nil, err
+			}
+			if Hash(b) != f.SHA256 {
+				s.State = Modified
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Path < statuses[j].Path })
+	return statuses, nil
+}
+
+// Hash returns the hex encoded sha256 hash of content
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Record records content as rendered from template for the file at path.
+func Record(path, template string, content []byte) error {
+	m, err := Load()
+	if err != nil {
+		return err
+	}
+	m.set(path, template, content)
+	return m.Save()
+}
+
+// Edited records an edit of the file at path by the scaffolding, changing its
+// content from before to after. Files changed by the user before the edit
+// keep being reported as modified, and files which are not tracked stay
+// untracked as they may have been changed by the user.
+func Edited(path string, before, after []byte) error {
+	m, err := Load()
+	if err != nil {
+		return err
+	}
+	f := m.Get(path)
+	if f == nil || f.SHA256 != Hash(before) {
+		return nil
+	}
+	m.set(path, "", after)
+	return m.Save()
+}
+
+// Removed stops tracking the files at paths, removed by the scaffolding.
+func Removed(paths ...string) error {
+	m, err := Load()
+	if err != nil {
+		return err
+	}
+	remove := map[string]bool{}
+	for _, p := range paths {
+		remove[filepath.ToSlash(filepath.Clean(p))] = true
+	}
+	files := m.Files[:0]
+	for _, f := range m.Files {
+		if !remove[f.Path] {
+			files = append(files, f)
+		}
+	}
+	if len(files) == len(m.Files) {
+		return nil
+	}
+	m.Files = files
+	return m.Save()
+}
+
+// Moved records the move of the file at from to to by the scaffolding,
+// changing its content from before to after. Files changed by the user
+// before the move keep being reported as modified.
+func Moved(from, to string, before, after []byte) error {
+	m, err := Load()
+	if err != nil {
+		return err
+	}
+	f := m.Get(from)
+	if f == nil {
+		return nil
+	}
+	f.Path = filepath.ToSlash(filepath.Clean(to))
+	if f.SHA256 == Hash(before) {
+		m.set(to, "", after)
+	}
+	return m.Save()
+}
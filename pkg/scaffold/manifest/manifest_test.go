@@ -0,0 +1,101 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func TestStatus(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	files := map[string]string{
+		"main.go":     "package main\n",
+		"Makefile":    "all:\n",
+		"Dockerfile":  "FROM scratch\n",
+		"config/a.go": "package config\n",
+	}
+	for path, content := range files {
+		if err := afero.WriteFile(filesystem.Fs, path, []byte(content), 0600); err != nil {
+			t.Fatalf("error %v", err)
+		}
+		if err := Record(path, "v2/Test", []byte(content)); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+
+	// modified by the user
+	if err := afero.WriteFile(filesystem.Fs, "Makefile", []byte("all: test\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	// removed by the user
+	if err := filesystem.Fs.Remove("Dockerfile"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	// edited by the scaffolding
+	if err := Edited("main.go", []byte("package main\n"), []byte("package main\n\nfunc main() {}\n")); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := afero.WriteFile(filesystem.Fs, "main.go", []byte("package main\n\nfunc main() {}\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	// edited by the scaffolding without being tracked, e.g. scaffolded
+	// before the manifest was introduced
+	if err := Edited("untracked.go", []byte("package main\n"), []byte("package main\n\nfunc main() {}\n")); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	// edited by the scaffolding after being modified by the user
+	if err := Edited("Makefile", []byte("all: test\n"), []byte("all: test\n")); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	m, err := Load()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	expected := []struct {
+		path  string
+		state State
+	}{
+		{"Dockerfile", Deleted},
+		{"Makefile", Modified},
+		{"config/a.go", Pristine},
+		{"main.go", Pristine},
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("got %d files and wanted %d", len(statuses), len(expected))
+	}
+	for i, e := range expected {
+		if statuses[i].Path != e.path || statuses[i].State != e.state {
+			t.Errorf("got: %s %s and wanted: %s %s", statuses[i].Path, statuses[i].State, e.path, e.state)
+		}
+		if statuses[i].Template != "v2/Test" {
+			t.Errorf("got template %q for %s", statuses[i].Template, statuses[i].Path)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/internal/diff"
+)
+
+// markerPrefix is the prefix of the comments marking where code is inserted
+// in scaffolded files, e.g. // +kubebuilder:scaffold:imports
+const markerPrefix = "+kubebuilder:scaffold:"
+
+// MarkersUsed returns the names of the markers the lines added to the file
+// of c were inserted at, e.g. scheme for // +kubebuilder:scaffold:scheme,
+// sorted. Code is inserted before its marker, so the marker of added lines
+// is the first one after them in the same paragraph. Created and removed
+// files have no markers.
+func MarkersUsed(c filesystem.Change) []string {
+	if c.Created() || c.Removed() {
+		return nil
+	}
+	lines := diff.SplitLines(string(c.New))
+	inserted := make([]bool, len(lines))
+	for _, op := range diff.Lines(diff.SplitLines(string(c.Old)), lines) {
+		if op.Kind == diff.Insert {
+			inserted[op.B] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	markers := []string{}
+	for i := range lines {
+		// only the last line of a run of inserted lines is followed by the marker
+		if !inserted[i] || (i+1 < len(lines) && inserted[i+1]) {
+			continue
+		}
+		for _, line := range lines[i+1:] {
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "}") || strings.HasPrefix(line, ")") {
+				break
+			}
+			if name := markerName(line); name != "" {
+				if !seen[name] {
+					seen[name] = true
+					markers = append(markers, name)
+				}
+				break
+			}
+		}
+	}
+	sort.Strings(markers)
+	return markers
+}
+
+// markerName returns the name of the marker comment line, empty if the line
+// is not a marker.
+func markerName(line string) string {
+	line = strings.TrimSpace(line)
+	for _, comment := range []string{"//", "#"} {
+		if strings.HasPrefix(line, comment) {
+			text := strings.TrimSpace(strings.TrimPrefix(line, comment))
+			if strings.HasPrefix(text, markerPrefix) {
+				return strings.TrimPrefix(text, markerPrefix)
+			}
+		}
+	}
+	return ""
+}
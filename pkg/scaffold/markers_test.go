@@ -0,0 +1,77 @@
+package scaffold_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+var _ = Describe("MarkersUsed", func() {
+	const mainGo = `func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	_ = crewv1.AddToScheme(scheme)
+	// +kubebuilder:scaffold:scheme
+}
+`
+
+	It("should return the markers code was inserted at", func() {
+		c := filesystem.Change{
+			Path: "main.go",
+			Old:  []byte(mainGo),
+			New: []byte(`func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	_ = crewv1.AddToScheme(scheme)
+	_ = shipv1.AddToScheme(scheme)
+	// +kubebuilder:scaffold:scheme
+}
+`),
+		}
+		Expect(scaffold.MarkersUsed(c)).To(Equal([]string{"scheme"}))
+
+		c = filesystem.Change{
+			Path: "config/crd/kustomization.yaml",
+			Old: []byte(`resources:
+- bases/crew.example.com_captains.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patches:
+# +kubebuilder:scaffold:crdkustomizewebhookpatch
+`),
+			New: []byte(`resources:
+- bases/crew.example.com_captains.yaml
+- bases/ship.example.com_frigates.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patches:
+#- patches/webhook_in_frigates.yaml
+# +kubebuilder:scaffold:crdkustomizewebhookpatch
+`),
+		}
+		Expect(scaffold.MarkersUsed(c)).To(Equal([]string{"crdkustomizeresource", "crdkustomizewebhookpatch"}))
+	})
+
+	It("should not return markers for changes away from them", func() {
+		c := filesystem.Change{
+			Path: "main.go",
+			Old:  []byte(mainGo),
+			New: []byte(`func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	_ = crewv1.AddToScheme(scheme)
+	// +kubebuilder:scaffold:scheme
+}
+`),
+		}
+		Expect(scaffold.MarkersUsed(c)).To(BeEmpty())
+	})
+
+	It("should not return markers for created files", func() {
+		c := filesystem.Change{Path: "main.go", New: []byte(mainGo)}
+		Expect(scaffold.MarkersUsed(c)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/internal/diff"
+)
+
+// MetadataDir is the directory of a project holding the data kubebuilder
+// keeps about the scaffolded files
+const MetadataDir = ".kubebuilder"
+
+// mergeBaseDir is the directory keeping the last rendered content of the
+// files scaffolded with the Merge action
+var mergeBaseDir = filepath.Join(MetadataDir, "base")
+
+// merge three-way merges the rendered content of the file at path with its
+// current content, using the last rendered content as base. Files merged
+// with conflicts are recorded in s.Conflicts.
+func (s *Scaffold) merge(path string, current, rendered []byte) ([]byte, error) {
+	base, err := loadMergeBase(path)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, conflicts := diff.Merge(base, diff.SplitLines(string(current)), diff.SplitLines(string(rendered)),
+		"current", "template")
+	if conflicts > 0 {
+		s.Conflicts = append(s.Conflicts, path)
+	}
+	return []byte(strings.Join(merged, "")), nil
+}
+
+// loadMergeBase returns the lines last rendered for the file at path, or nil
+// if the file has no base, e.g. it was scaffolded before the Merge action
+// was used for it.
+func loadMergeBase(path string) ([]string, error) {
+	p := filepath.Join(mergeBaseDir, path)
+	exists, err := afero.Exists(filesystem.Fs, p)
+	if err != nil || !exists {
+		return nil, err
+	}
+	b, err := afero.ReadFile(filesystem.Fs, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read merge base %s: %v", p, err)
+	}
+	lines := diff.SplitLines(string(b))
+	if lines == nil {
+		// an empty base is still a base
+		lines = []string{}
+	}
+	return lines, nil
+}
+
+// saveMergeBase saves the rendered content of the file at path as base for
+// its next merge.
+func saveMergeBase(path string, rendered []byte) error {
+	p := filepath.Join(mergeBaseDir, path)
+	if err := filesystem.Fs.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(p), err)
+	}
+	if err := afero.WriteFile(filesystem.Fs, p, rendered, 0600); err != nil {
+		return fmt.Errorf("failed to save merge base %s: %v", p, err)
+	}
+	return nil
+}
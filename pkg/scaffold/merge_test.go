@@ -0,0 +1,76 @@
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// mergedFile is a file scaffolded with the Merge action
+type mergedFile struct {
+	input.Input
+	Header string
+	Footer string
+}
+
+func (f *mergedFile) GetInput() (input.Input, error) {
+	f.Path = "merged.txt"
+	f.TemplateBody = "{{ .Header }}\n\nbody\n{{ .Footer }}\n"
+	f.IfExistsAction = input.Merge
+	return f.Input, nil
+}
+
+var _ = Describe("Merge", func() {
+	var oldFs afero.Fs
+	var s *scaffold.Scaffold
+
+	BeforeEach(func() {
+		oldFs = filesystem.Fs
+		filesystem.Fs = afero.NewMemMapFs()
+		s = &scaffold.Scaffold{
+			BoilerplateOptional: true,
+			ProjectOptional:     true,
+		}
+	})
+
+	AfterEach(func() {
+		filesystem.Fs = oldFs
+	})
+
+	read := func(path string) string {
+		b, err := afero.ReadFile(filesystem.Fs, path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("should keep the rendered content as base", func() {
+		Expect(s.Execute(input.Options{}, &mergedFile{Header: "h1", Footer: "f1"})).To(Succeed())
+		Expect(read("merged.txt")).To(Equal("h1\n\nbody\nf1\n"))
+		Expect(read(filepath.Join(".kubebuilder", "base", "merged.txt"))).To(Equal("h1\n\nbody\nf1\n"))
+	})
+
+	It("should merge the template changes with the changes to the file", func() {
+		Expect(s.Execute(input.Options{}, &mergedFile{Header: "h1", Footer: "f1"})).To(Succeed())
+		Expect(afero.WriteFile(filesystem.Fs, "merged.txt", []byte("h1\n\nedited\nf1\n"), 0600)).To(Succeed())
+
+		Expect(s.Execute(input.Options{}, &mergedFile{Header: "h2", Footer: "f1"})).To(Succeed())
+		Expect(read("merged.txt")).To(Equal("h2\n\nedited\nf1\n"))
+		Expect(read(filepath.Join(".kubebuilder", "base", "merged.txt"))).To(Equal("h2\n\nbody\nf1\n"))
+		Expect(s.Conflicts).To(BeEmpty())
+	})
+
+	It("should write conflict markers when the changes disagree", func() {
+		Expect(s.Execute(input.Options{}, &mergedFile{Header: "h1", Footer: "f1"})).To(Succeed())
+		Expect(afero.WriteFile(filesystem.Fs, "merged.txt", []byte("h1\n\nbody\nmine\n"), 0600)).To(Succeed())
+
+		Expect(s.Execute(input.Options{}, &mergedFile{Header: "h1", Footer: "f2"})).To(Succeed())
+		Expect(read("merged.txt")).To(Equal("h1\n\nbody\n<<<<<<< current\nmine\n=======\nf2\n>>>>>>> template\n"))
+		Expect(s.Conflicts).To(Equal([]string{"merged.txt"}))
+	})
+})
@@ -0,0 +1,990 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	resourcev1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	resourcev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+)
+
+// MigrateProjectFile migrates p to the project version, one version at a
+// time with the MigrateProject of the layouts in between. Only the PROJECT
+// file is migrated, not the files of the project.
+func MigrateProjectFile(p *input.ProjectFile, version string) error {
+	if _, err := GetLayout(version); err != nil {
+		return err
+	}
+	for p.Version != version {
+		next := nextLayout(p.Version)
+		if next == nil {
+			return fmt.Errorf("cannot migrate PROJECT from version %s to %s", p.Version, version)
+		}
+		if err := next.MigrateProject(p); err != nil {
+			return fmt.Errorf("error migrating PROJECT from version %s to %s: %v", p.Version, next.Version, err)
+		}
+		p.Version = next.Version
+		if errs := ValidateProjectFile(p); len(errs) > 0 {
+			return &ProjectFileError{Path: "migrated PROJECT", Fields: errs}
+		}
+	}
+	return nil
+}
+
+// nextLayout returns the layout migrating PROJECT files of the version, nil
+// if there is none.
+func nextLayout(version string) *Layout {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	for _, l := range layouts {
+		if l.MigrateFrom == version && l.MigrateProject != nil {
+			return l
+		}
+	}
+	return nil
+}
+
+// MigrateProjectV2 migrates a version 1 PROJECT file, which has no resources,
+// by discovering the resources from the types in pkg/apis and the
+// controllers in pkg/controller. Projects with more than one group get the
+// multi group layout.
+func MigrateProjectV2(p *input.ProjectFile) error {
+	resources, err := discoverResourcesV1()
+	if err != nil {
+		return err
+	}
+	groups := map[string]bool{}
+	for _, r := range resources {
+		groups[r.Group] = true
+	}
+	p.MultiGroup = len(groups) > 1
+	p.Resources = resources
+	return nil
+}
+
+// discoverResourcesV1 returns the resources of a version 1 project: the
+// struct types of pkg/apis/<group>/<version> with a list type, e.g. Frigate
+// with FrigateList, and whether pkg/controller/<kind> has their controller.
+// The controllers of other kinds are added as resources without API.
+func discoverResourcesV1() ([]input.Resource, error) {
+	dirs, err := afero.Glob(filesystem.Fs, filepath.Join("pkg", "apis", "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+
+	var resources []input.Resource
+	for _, dir := range dirs {
+		if info, err := filesystem.Fs.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		group, version := filepath.Base(filepath.Dir(dir)), filepath.Base(dir)
+		kinds, err := discoverKinds(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range kinds {
+			lower := strings.ToLower(k.name)
+			controller := filepath.Join("pkg", "controller", lower, lower+"_controller.go")
+			resources = append(resources, input.Resource{
+				Group:      group,
+				Version:    version,
+				Kind:       k.name,
+				API:        &input.ResourceAPI{Namespaced: k.namespaced},
+				Controller: exists(controller),
+			})
+		}
+	}
+
+	dirs, err = afero.Glob(filesystem.Fs, filepath.Join("pkg", "controller", "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		lower := filepath.Base(dir)
+		controller := filepath.Join(dir, lower+"_controller.go")
+		if !exists(controller) || v1ControllerKind(resources, lower) != nil {
+			continue
+		}
+		r, err := discoverControllerV1(controller)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			resources = append(resources, *r)
+		}
+	}
+	return resources, nil
+}
+
+// v1ControllerKind returns the resource whose controller is in the directory
+// pkg/controller/<lower> of a version 1 project, nil if there is none.
+func v1ControllerKind(resources []input.Resource, lower string) *input.Resource {
+	for i := range resources {
+		if strings.ToLower(resources[i].Kind) == lower {
+			return &resources[i]
+		}
+	}
+	return nil
+}
+
+// discoverControllerV1 returns the resource of the controller in the Go file
+// filename of a version 1 project, whose kind is the one of its reconciler,
+// e.g. ReconcileDeployment, and whose group version is the package of the
+// kind. It returns nil if the controller has no reconciler of a kind.
+func discoverControllerV1(filename string) (*input.Resource, error) {
+	src, err := afero.ReadFile(filesystem.Fs, filename)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filename, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+
+	kind := ""
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.StructType); ok && strings.HasPrefix(ts.Name.Name, "Reconcile") && kind == "" {
+				kind = strings.TrimPrefix(ts.Name.Name, "Reconcile")
+			}
+		}
+	}
+	if kind == "" {
+		return nil, nil
+	}
+
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = p
+	}
+	var pkg string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == kind && pkg == "" {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				pkg = imports[x.Name]
+			}
+		}
+		return pkg == ""
+	})
+	if pkg == "" {
+		return nil, nil
+	}
+
+	r := &input.Resource{
+		Group:      path.Base(path.Dir(pkg)),
+		Version:    path.Base(pkg),
+		Kind:       kind,
+		Controller: true,
+	}
+	r.Core = strings.HasPrefix(pkg, "k8s.io/api/") && util.IsCoreGroup(r.Group)
+	r.External = !r.Core
+	return r, nil
+}
+
+// discoveredKind is a kind found in a Go package of types
+type discoveredKind struct {
+	name       string
+	namespaced bool
+}
+
+// discoverKinds returns the kinds of the Go package in dir, sorted by name.
+// A kind is cluster scoped if the comments before it have the marker
+// +genclient:nonNamespaced.
+func discoverKinds(dir string) ([]discoveredKind, error) {
+	paths, err := afero.Glob(filesystem.Fs, filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	structs := map[string]bool{}
+	clusterScoped := map[string]bool{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || strings.HasPrefix(filepath.Base(path), "zz_generated") {
+			continue
+		}
+		src, err := afero.ReadFile(filesystem.Fs, path)
+		if err != nil {
+			return nil, err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		prevEnd := file.Name.End()
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if ok && gen.Tok == token.TYPE {
+				markers := commentsBetween(file, prevEnd, gen.Pos())
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if _, ok := ts.Type.(*ast.StructType); !ok {
+						continue
+					}
+					structs[ts.Name.Name] = true
+					if strings.Contains(markers, "+genclient:nonNamespaced") {
+						clusterScoped[ts.Name.Name] = true
+					}
+				}
+			}
+			prevEnd = decl.End()
+		}
+	}
+
+	var kinds []discoveredKind
+	for name := range structs {
+		if structs[name+"List"] {
+			kinds = append(kinds, discoveredKind{name: name, namespaced: !clusterScoped[name]})
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].name < kinds[j].name })
+	return kinds, nil
+}
+
+// commentsBetween returns the text of the comments of file between the
+// positions.
+func commentsBetween(file *ast.File, from, to token.Pos) string {
+	var text []string
+	for _, cg := range file.Comments {
+		if cg.Pos() >= from && cg.End() <= to {
+			for _, c := range cg.List {
+				text = append(text, c.Text)
+			}
+		}
+	}
+	return strings.Join(text, "\n")
+}
+
+// Migrate migrates a project to the layout of the next project version: the
+// files of the project are moved and converted to the layout, and the files
+// of the layout are scaffolded. The code which cannot be converted is kept
+// for the user to port, see Unconverted.
+type Migrate struct {
+	// Moved are the paths of the files moved by Migrate, by their new path
+	Moved map[string]string
+
+	// Unconverted is the code Migrate could not convert
+	Unconverted []Unconverted
+}
+
+// Unconverted is a file Migrate could not convert
+type Unconverted struct {
+	// Path is the path of the file
+	Path string
+
+	// KeptAs is the path the file is moved to, so the project builds without
+	// it, empty if the file is left in place
+	KeptAs string
+
+	// Reason is why the file is not converted and what to do with it
+	Reason string
+}
+
+// Migrate migrates the project in the current directory.
+func (m *Migrate) Migrate() error {
+	p, err := LoadProjectFile("PROJECT")
+	if err != nil {
+		return err
+	}
+	l := nextLayout(p.Version)
+	if l == nil || l.Migrate == nil {
+		return fmt.Errorf("kubebuilder alpha migrate is not supported for project version %s", p.Version)
+	}
+	m.Moved = map[string]string{}
+	return l.Migrate(m, &p)
+}
+
+// v1Dirs are the directories of the Go packages of the v1 layout, which are
+// not in the v2 layout
+var v1Dirs = []string{
+	filepath.Join("pkg", "apis"),
+	filepath.Join("pkg", "controller"),
+	filepath.Join("pkg", "webhook"),
+	filepath.Join("cmd", "manager"),
+}
+
+// generatedV1 are the manifests generated by make in version 1 projects, the
+// v2 layout generates them at other paths
+var generatedV1 = []string{
+	filepath.Join("config", "crds"),
+	filepath.Join("config", "rbac", "rbac_role.yaml"),
+	filepath.Join("config", "rbac", "rbac_role_binding.yaml"),
+	filepath.Join("config", "webhook", "webhook.yaml"),
+}
+
+// MigrateV2 migrates a version 1 project to the v2 layout. The types of
+// pkg/apis/<group>/<version> move to the API packages, and the controllers
+// of pkg/controller/<kind> to the controllers packages, where the Add
+// function of a controller becomes the SetupWithManager method of its
+// reconciler. The files scaffolded by version 1 are replaced by the ones of
+// the v2 layout, main.go wires the types and the controllers, and the
+// constraints of Gopkg.toml become the requirements of go.mod.
+func (m *Migrate) MigrateV2(v1 *input.ProjectFile) error {
+	p := *v1
+	if err := MigrateProjectFile(&p, project.Version2); err != nil {
+		return err
+	}
+	resources := make([]*resourcev1.Resource, len(p.Resources))
+	for i, res := range p.Resources {
+		r := &resourcev1.Resource{
+			Group:      res.Group,
+			Version:    res.Version,
+			Kind:       res.Kind,
+			Namespaced: res.API == nil || res.API.Namespaced,
+		}
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		p.Resources[i].Plural = r.Resource
+		resources[i] = r
+	}
+
+	pristine, err := renderV1(p.Resources, resources)
+	if err != nil {
+		return err
+	}
+	boilerplate, err := getBoilerplate(filepath.Join("hack", "boilerplate.go.txt"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// the moves are planned before any file is touched
+	migration := resourcev2.NewMigration(&p)
+	apiFiles, err := m.planAPIs(&p, resources, migration)
+	if err != nil {
+		return err
+	}
+	controllers, err := m.planControllers(&p, resources)
+	if err != nil {
+		return err
+	}
+	var generated []string
+	for _, dir := range append([]string{filepath.Join("pkg", "apis")}, generatedV1...) {
+		paths, err := filesUnder(dir)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if dir != filepath.Join("pkg", "apis") || strings.HasPrefix(filepath.Base(path), "zz_generated") {
+				generated = append(generated, path)
+			}
+		}
+	}
+
+	if err := m.replaceV1Files(pristine); err != nil {
+		return err
+	}
+	if err := removeFiles(generated...); err != nil {
+		return err
+	}
+	for _, path := range sortedKeys(apiFiles) {
+		if err := migration.APIFile(path, apiFiles[path]); err != nil {
+			return fmt.Errorf("error migrating %s: %v", path, err)
+		}
+		m.Moved[apiFiles[path]] = path
+	}
+	for _, c := range controllers {
+		for _, path := range sortedKeys(c.files) {
+			setup, err := migration.ControllerFile(c.resource, path, c.files[path], c.idents)
+			if err != nil {
+				return fmt.Errorf("error migrating %s: %v", path, err)
+			}
+			c.setup = c.setup || setup
+			m.Moved[c.files[path]] = path
+		}
+	}
+	if err := m.keepV1Leftovers(); err != nil {
+		return err
+	}
+	goPaths, err := goFilePaths()
+	if err != nil {
+		return err
+	}
+	for _, path := range goPaths {
+		if _, err := migration.GoFile(path); err != nil {
+			return fmt.Errorf("error migrating %s: %v", path, err)
+		}
+	}
+	require, replace, err := m.convertGopkg()
+	if err != nil {
+		return err
+	}
+
+	// the PROJECT file is scaffolded again by init
+	if err := removeFiles("PROJECT"); err != nil {
+		return err
+	}
+	err = (&V2Project{
+		Project: project.Project{ProjectFile: input.ProjectFile{
+			Version:     p.Version,
+			Domain:      p.Domain,
+			Repo:        p.Repo,
+			TemplateDir: p.TemplateDir,
+			MultiGroup:  p.MultiGroup,
+		}},
+		Boilerplate: project.Boilerplate{Input: input.Input{Boilerplate: boilerplate}},
+	}).Scaffold()
+	if err != nil {
+		return err
+	}
+	err = (&Scaffold{}).Execute(input.Options{}, &resourcev2.GoMod{Require: require, Replace: replace})
+	if err != nil {
+		return fmt.Errorf("error scaffolding go.mod: %v", err)
+	}
+	for i, res := range p.Resources {
+		if res.API == nil {
+			continue
+		}
+		if err := scaffoldMigratedAPI(&p, resources[i]); err != nil {
+			return err
+		}
+	}
+	for _, c := range controllers {
+		if err := m.wireMigratedController(&p, c); err != nil {
+			return err
+		}
+	}
+
+	if err := saveProjectFile("PROJECT", &p); err != nil {
+		return fmt.Errorf("error updating project file with resource information: %v", err)
+	}
+	return nil
+}
+
+// renderV1 renders the files version 1 scaffolds for the project and its
+// resources, the resources of res, by path. A file may have different
+// contents, e.g. with or without the example reconcile body.
+func renderV1(res []input.Resource, resources []*resourcev1.Resource) (map[string][][]byte, error) {
+	rendered := map[string][][]byte{}
+	var buffers []*bytes.Buffer
+	var paths []string
+	s := &Scaffold{
+		BoilerplateOptional: true,
+		GetWriter: func(path string) (io.Writer, error) {
+			b := &bytes.Buffer{}
+			buffers, paths = append(buffers, b), append(paths, path)
+			return b, nil
+		},
+		FileExists: func(string) bool { return false },
+	}
+
+	var files []input.File
+	for _, f := range (&V1Project{}).files() {
+		// Gopkg.toml is converted to go.mod
+		if _, ok := f.(*project.GopkgToml); !ok {
+			files = append(files, f)
+		}
+	}
+	for i, r := range resources {
+		if res[i].API != nil {
+			files = append(files, v1APIFiles(r)...)
+		}
+		if !res[i].Controller {
+			continue
+		}
+		withExample := *r
+		withExample.CreateExampleReconcileBody = res[i].API != nil
+		files = append(files, v1ControllerFiles(&withExample)...)
+		if withExample.CreateExampleReconcileBody {
+			files = append(files, v1ControllerFiles(r)...)
+		}
+	}
+	if err := s.Execute(input.Options{}, files...); err != nil {
+		return nil, fmt.Errorf("error rendering the files of the v1 layout: %v", err)
+	}
+	for i, path := range paths {
+		rendered[path] = append(rendered[path], buffers[i].Bytes())
+	}
+	return rendered, nil
+}
+
+// replaceV1Files removes the files scaffolded by version 1 which the v2 layout
+// replaces, the pristine files by path. Changed files are kept for the user
+// to port the changes. The types, the controllers and the samples are
+// migrated instead.
+func (m *Migrate) replaceV1Files(pristine map[string][][]byte) error {
+	paths := make([]string, 0, len(pristine))
+	for path := range pristine {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var unchanged []string
+	for _, path := range paths {
+		dir, name := filepath.Dir(path), filepath.Base(path)
+		switch {
+		case strings.HasPrefix(dir, filepath.Join("pkg", "apis")) && strings.HasSuffix(name, "_types.go"),
+			strings.HasPrefix(dir, filepath.Join("pkg", "controller")+string(filepath.Separator)) &&
+				strings.HasSuffix(name, "_controller.go"),
+			dir == filepath.Join("config", "samples"),
+			!exists(path):
+			continue
+		}
+		b, err := afero.ReadFile(filesystem.Fs, path)
+		if err != nil {
+			return err
+		}
+		changed := true
+		for _, content := range pristine[path] {
+			if bytes.Equal(b, content) {
+				changed = false
+			}
+		}
+		if !changed {
+			unchanged = append(unchanged, path)
+			continue
+		}
+		if err := m.keepV1(path, v1Reason(path)); err != nil {
+			return err
+		}
+	}
+	return removeFiles(unchanged...)
+}
+
+// keepV1Leftovers keeps the files left in the directories of the v1 layout,
+// which are not converted. Go files are moved so the project builds without
+// them.
+func (m *Migrate) keepV1Leftovers() error {
+	for _, dir := range v1Dirs {
+		paths, err := filesUnder(dir)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if filepath.Ext(path) != ".go" {
+				m.Unconverted = append(m.Unconverted, Unconverted{
+					Path:   path,
+					Reason: "the directory is not in the v2 layout, move the file",
+				})
+				continue
+			}
+			if err := m.keepV1(path, v1Reason(path)); err != nil {
+				return err
+			}
+		}
+	}
+	if exists("vendor") && exists("Gopkg.toml") {
+		m.Unconverted = append(m.Unconverted, Unconverted{
+			Path:   "vendor",
+			Reason: "the dependencies vendored by dep are not used by go modules, remove them or run go mod vendor",
+		})
+	}
+	return nil
+}
+
+// keepV1 moves the file at path of the v1 layout to path.v1 and records it as
+// unconverted for the reason.
+func (m *Migrate) keepV1(path, reason string) error {
+	kept := path + ".v1"
+	if err := moveFile(path, kept); err != nil {
+		return err
+	}
+	m.Moved[kept] = path
+	m.Unconverted = append(m.Unconverted, Unconverted{Path: path, KeptAs: kept, Reason: reason})
+	return nil
+}
+
+// v1Reason returns why the file at path of the v1 layout is not converted.
+func v1Reason(path string) string {
+	dir, name := filepath.Dir(path), filepath.Base(path)
+	apis, controllers := filepath.Join("pkg", "apis"), filepath.Join("pkg", "controller")
+	switch {
+	case strings.HasSuffix(name, "_test.go"):
+		return "the tests of the v1 layout are not converted, port them to the test suites of the controllers"
+	case path == filepath.Join("cmd", "manager", "main.go"):
+		return "the manager is set up in main.go in the v2 layout, port the changes to it"
+	case strings.HasPrefix(path, filepath.Join("pkg", "webhook")+string(filepath.Separator)):
+		return "the webhooks of the v1 layout are not converted, " +
+			"scaffold them with kubebuilder create webhook and port their handlers"
+	case dir == apis || (filepath.Dir(dir) == apis && name == "group.go"):
+		return "the types are added to the scheme in main.go in the v2 layout, port the changes to it"
+	case strings.HasPrefix(dir, apis) && (name == "register.go" || name == "doc.go"):
+		return "the group version is registered in groupversion_info.go in the v2 layout, port the changes to it"
+	case dir == controllers:
+		return "the controllers are set up with the manager in main.go in the v2 layout, port the changes to it"
+	case strings.HasPrefix(dir, apis), strings.HasPrefix(dir, controllers):
+		return "the package is not in the v2 layout, move the code to a package of the project"
+	}
+	return "the file of the v1 layout was changed, port the changes to the file of the v2 layout"
+}
+
+// planAPIs returns the new paths of the Go files of the types packages by
+// their paths, and adds the moves of the packages to the migration. The
+// tests and the generated files are not moved.
+func (m *Migrate) planAPIs(p *input.ProjectFile, resources []*resourcev1.Resource,
+	migration *resourcev2.Migration) (map[string]string, error) {
+	files := map[string]string{}
+	planned := map[string]bool{}
+	for i, res := range p.Resources {
+		r := resources[i]
+		dir := filepath.Join("pkg", "apis", r.Group, r.Version)
+		if res.API == nil || planned[dir] {
+			continue
+		}
+		planned[dir] = true
+
+		paths, err := afero.Glob(filesystem.Fs, filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		var sources []string
+		for _, path := range paths {
+			name := filepath.Base(path)
+			if strings.HasSuffix(name, "_test.go") || strings.HasPrefix(name, "zz_generated") {
+				continue
+			}
+			// the uses of the identifiers of all the files are moved
+			sources = append(sources, path)
+			if name != "register.go" && name != "doc.go" {
+				files[path] = filepath.Join(util.APIDir(r, p.MultiGroup), name)
+			}
+		}
+		if err := migration.AddAPI(r, sources); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// migratedController is a controller of pkg/controller/<kind> of the v1
+// layout moved to a controllers package
+type migratedController struct {
+	resource *resourcev1.Resource
+
+	// files are the new paths of the Go files of the controller by their
+	// paths
+	files map[string]string
+
+	// idents are the renamed identifiers of the controller package
+	idents map[string]string
+
+	// setup is set if the reconciler got a SetupWithManager method
+	setup bool
+}
+
+// planControllers returns the controllers moved to the controllers packages.
+// The identifiers of a controller package colliding with the ones of the
+// other controllers of the same package are prefixed with the kind. The
+// tests are not moved.
+func (m *Migrate) planControllers(p *input.ProjectFile, resources []*resourcev1.Resource) ([]*migratedController, error) {
+	dirs, err := afero.Glob(filesystem.Fs, filepath.Join("pkg", "controller", "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+
+	var controllers []*migratedController
+	// the names declared in the controllers packages, by package
+	declared := map[string]map[string]bool{}
+	for _, dir := range dirs {
+		lower := filepath.Base(dir)
+		var r *resourcev1.Resource
+		for i, res := range p.Resources {
+			if res.Controller && strings.ToLower(res.Kind) == lower {
+				r = resources[i]
+			}
+		}
+		if r == nil {
+			continue
+		}
+		pkg := util.ControllersDir(r.Group, p.MultiGroup)
+		if declared[pkg] == nil {
+			declared[pkg] = map[string]bool{}
+		}
+		c := &migratedController{
+			resource: r,
+			files:    map[string]string{},
+			idents:   resourcev2.ControllerIdents(r.Kind),
+		}
+
+		paths, err := afero.Glob(filesystem.Fs, filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			name := filepath.Base(path)
+			if strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			if !strings.HasPrefix(name, lower) {
+				name = lower + "_" + name
+			}
+			c.files[path] = filepath.Join(pkg, name)
+
+			names, err := resourcev2.DeclaredNames(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				if name == "Add" {
+					// Add becomes a method of the reconciler
+					continue
+				}
+				renamed, ok := c.idents[name]
+				if !ok {
+					renamed = name
+				}
+				if declared[pkg][renamed] {
+					renamed = prefixName(r.Kind, name)
+					c.idents[name] = renamed
+				}
+				declared[pkg][renamed] = true
+			}
+		}
+		controllers = append(controllers, c)
+	}
+	return controllers, nil
+}
+
+// prefixName returns name prefixed with the kind, exported if name is.
+func prefixName(kind, name string) string {
+	if !ast.IsExported(name) {
+		kind = strings.ToLower(kind[:1]) + kind[1:]
+	}
+	return kind + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// scaffoldMigratedAPI scaffolds the files of the v2 layout for the types of
+// r moved to its API package, and wires them in main.go.
+func scaffoldMigratedAPI(p *input.ProjectFile, r *resourcev1.Resource) error {
+	files := []input.File{
+		&resourcev2.Group{Resource: r},
+		&crdv2.EnableWebhookPatch{Resource: r},
+		&crdv2.EnableCAInjectionPatch{Resource: r},
+	}
+	// the samples have the same paths in both layouts
+	sample := &resourcev2.CRDSample{Resource: r}
+	samplePath, err := (&Scaffold{}).path(sample)
+	if err != nil {
+		return err
+	}
+	if !exists(samplePath) {
+		files = append(files, sample)
+	}
+	for _, f := range files {
+		err := (&Scaffold{}).Execute(input.Options{}, f)
+		if err != nil && !isAlreadyExistsError(err) {
+			return fmt.Errorf("error scaffolding APIs: %v", err)
+		}
+	}
+
+	crdKustomization := &crdv2.Kustomization{Resource: r}
+	err = (&Scaffold{}).Execute(input.Options{}, crdKustomization, &crdv2.KustomizeConfig{})
+	if err != nil && !isAlreadyExistsError(err) {
+		return fmt.Errorf("error scaffolding kustomization: %v", err)
+	}
+	if err := crdKustomization.Update(); err != nil {
+		return fmt.Errorf("error updating kustomization.yaml: %v", err)
+	}
+
+	err = (&resourcev2.Main{}).Update(&resourcev2.MainUpdateOptions{
+		Project:      p,
+		WireResource: true,
+		Resource:     r,
+	})
+	if err != nil {
+		return withContext("error updating main.go", err)
+	}
+	return nil
+}
+
+// wireMigratedController sets up the reconciler of the controller in main.go
+// and the test suite of its package. A reconciler without SetupWithManager
+// is left for the user to set up.
+func (m *Migrate) wireMigratedController(p *input.ProjectFile, c *migratedController) error {
+	r := c.resource
+	suite := &resourcev2.ControllerSuiteTest{Resource: r}
+	err := (&Scaffold{}).Execute(input.Options{}, suite)
+	if err != nil && !isAlreadyExistsError(err) {
+		return fmt.Errorf("error scaffolding controller: %v", err)
+	}
+	if err := suite.Update(); err != nil {
+		return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+	}
+
+	if !c.setup {
+		path := filepath.Join(util.ControllersDir(r.Group, p.MultiGroup), strings.ToLower(r.Kind)+"_controller.go")
+		m.Unconverted = append(m.Unconverted, Unconverted{
+			Path: path,
+			Reason: fmt.Sprintf("the controller has no Add function, "+
+				"add a SetupWithManager method to %sReconciler and set it up in main.go", r.Kind),
+		})
+		return nil
+	}
+	err = (&resourcev2.Main{}).Update(&resourcev2.MainUpdateOptions{
+		Project:        p,
+		WireController: true,
+		Migrated:       true,
+		Resource:       r,
+	})
+	if err != nil {
+		return withContext("error updating main.go", err)
+	}
+	return nil
+}
+
+// convertGopkg converts the constraints and overrides of the part of
+// Gopkg.toml owned by the user to requirements and replace directives of
+// go.mod, and removes the files of dep. Gopkg.toml is kept if some of them
+// cannot be converted.
+func (m *Migrate) convertGopkg() (require, replace []string, err error) {
+	if !exists("Gopkg.toml") {
+		return nil, nil, nil
+	}
+	b, err := afero.ReadFile(filesystem.Fs, "Gopkg.toml")
+	if err != nil {
+		return nil, nil, err
+	}
+	tables, err := project.ParseGopkg(project.UserGopkgContent(b))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing Gopkg.toml: %v", err)
+	}
+
+	var reasons []string
+	for _, t := range tables {
+		if t.Name != "constraint" && t.Name != "override" {
+			continue
+		}
+		name := t.Values["name"]
+		if name == "" {
+			continue
+		}
+		if name == "sigs.k8s.io/controller-runtime" {
+			reasons = append(reasons, fmt.Sprintf("the %s of %s is not converted, "+
+				"the v2 layout requires its version of go.mod", t.Name, name))
+			continue
+		}
+		version, ok := depModuleVersion(name, t.Values["version"])
+		if !ok {
+			on := "version " + t.Values["version"]
+			for _, key := range []string{"branch", "revision"} {
+				if v := t.Values[key]; v != "" {
+					on = key + " " + v
+				}
+			}
+			reasons = append(reasons, fmt.Sprintf("the %s of %s on %s is not converted, "+
+				"require the version of the module in go.mod", t.Name, name, on))
+			continue
+		}
+		if t.Name == "constraint" {
+			require = append(require, name+" "+version)
+		}
+		if source := t.Values["source"]; t.Name == "override" || source != "" {
+			replace = append(replace, depReplace(name, source, version))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return require, replace, removeFiles("Gopkg.toml", "Gopkg.lock")
+	}
+	if err := removeFiles("Gopkg.lock"); err != nil {
+		return nil, nil, err
+	}
+	if err := m.keepV1("Gopkg.toml", strings.Join(reasons, "; ")); err != nil {
+		return nil, nil, err
+	}
+	return require, replace, nil
+}
+
+// filesUnder returns the paths of the files under path, which may be a file,
+// sorted. It is empty if path does not exist.
+func filesUnder(path string) ([]string, error) {
+	var paths []string
+	if !exists(path) {
+		return nil, nil
+	}
+	err := afero.Walk(filesystem.Fs, path, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			// the files removed in an overlay are still listed in their
+			// directory
+			return nil
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// moveFile moves the file at from to to.
+func moveFile(from, to string) error {
+	if exists(to) {
+		return fmt.Errorf("%s already exists", to)
+	}
+	info, err := filesystem.Fs.Stat(from)
+	if err != nil {
+		return err
+	}
+	b, err := afero.ReadFile(filesystem.Fs, from)
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(filesystem.Fs, to, b, info.Mode()); err != nil {
+		return err
+	}
+	if err := filesystem.Fs.Remove(from); err != nil {
+		return err
+	}
+	return manifest.Moved(from, to, b, b)
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -19,11 +19,12 @@ package scaffold
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 )
 
 // FileWriter is a io wrapper to write files
@@ -34,7 +35,7 @@ type FileWriter struct {
 // WriteCloser returns a WriteCloser to write to given path
 func (fw *FileWriter) WriteCloser(path string) (io.Writer, error) {
 	if fw.Fs == nil {
-		fw.Fs = afero.NewOsFs()
+		fw.Fs = filesystem.Fs
 	}
 	dir := filepath.Dir(path)
 	err := fw.Fs.MkdirAll(dir, 0700)
@@ -51,9 +52,9 @@ func (fw *FileWriter) WriteCloser(path string) (io.Writer, error) {
 }
 
 // WriteFile write given content to the file path
-func (fw *FileWriter) WriteFile(filePath string, content []byte) error {
+func (fw *FileWriter) WriteFile(filePath string, content []byte) (err error) {
 	if fw.Fs == nil {
-		fw.Fs = afero.NewOsFs()
+		fw.Fs = filesystem.Fs
 	}
 	f, err := fw.WriteCloser(filePath)
 	if err != nil {
@@ -62,8 +63,8 @@ func (fw *FileWriter) WriteFile(filePath string, content []byte) error {
 
 	if c, ok := f.(io.Closer); ok {
 		defer func() {
-			if err := c.Close(); err != nil {
-				log.Fatal(err)
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = fmt.Errorf("failed to close %s: %v", filePath, cerr)
 			}
 		}()
 	}
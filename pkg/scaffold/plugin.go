@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+// NewPluginRequest returns the request for a plugin run with args and flags
+// in the current project, if any.
+func NewPluginRequest(args []string, flags map[string]string) (*plugin.Request, error) {
+	req := &plugin.Request{APIVersion: plugin.APIVersion, Args: args, Flags: flags}
+
+	exists, err := afero.Exists(filesystem.Fs, "PROJECT")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		p, err := LoadProjectFile("PROJECT")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the PROJECT file: %v", err)
+		}
+		req.Project = &p
+	}
+
+	b, err := getBoilerplate(filepath.Join("hack", "boilerplate.go.txt"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	req.Boilerplate = b
+	return req, nil
+}
+
+// ApplyPlugin writes the files and applies the edits of the response of a
+// plugin, and adds its resources to the PROJECT file. It returns the files
+// merged with conflicts.
+func ApplyPlugin(resp *plugin.Response) ([]string, error) {
+	if err := resp.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Scaffold{BoilerplateOptional: true, ProjectOptional: true}
+	var files []input.File
+	for _, f := range resp.Files {
+		files = append(files, f.Template())
+	}
+	if err := s.Execute(input.Options{}, files...); err != nil {
+		return nil, err
+	}
+
+	for _, e := range resp.Edits {
+		if err := applyPluginEdit(e); err != nil {
+			return nil, fmt.Errorf("failed to edit %s: %v", e.Path, err)
+		}
+	}
+
+	if len(resp.Resources) > 0 {
+		if err := addResources(resp.Resources); err != nil {
+			return nil, err
+		}
+	}
+	return s.Conflicts, nil
+}
+
+// applyPluginEdit applies an edit of a plugin response.
+func applyPluginEdit(e plugin.Edit) error {
+	p := filepath.FromSlash(path.Clean(e.Path))
+	switch {
+	case e.Import != nil:
+		return scaffoldv2.AddImport(p, e.Import.Name, e.Import.Path)
+	case e.Insert != nil:
+		return scaffoldv2.InsertStatements(p, e.Insert.Func, e.Insert.Marker, e.Insert.Code)
+	default:
+		l := e.ListItem
+		return scaffoldv2.AddListItem(p, l.List, l.Item, l.Commented, l.Marker)
+	}
+}
+
+// addResources adds the resources missing from the PROJECT file.
+func addResources(resources []input.Resource) error {
+	p, err := LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+	if p.Version != project.Version2 {
+		return fmt.Errorf("resources are only tracked in the PROJECT file of version %s projects", project.Version2)
+	}
+	for _, r := range resources {
+		found := false
+		for _, existing := range p.Resources {
+			if existing.Is(r.Group, r.Version, r.Kind) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.Resources = append(p.Resources, r)
+		}
+	}
+	return saveProjectFile("PROJECT", &p)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Prefix is the prefix of the executable names of plugins
+const Prefix = "kubebuilder-"
+
+// Lookup returns the path of the plugin name on PATH, the first one found
+// if it is in more than one directory of PATH.
+func Lookup(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid plugin name %q", name)
+	}
+	return exec.LookPath(Prefix + name)
+}
+
+// Call runs the plugin at path with req and returns its response. The
+// standard error of the plugin is passed through to stderr.
+func Call(path string, req *Request, stderr io.Writer) (*Response, error) {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	out := &bytes.Buffer{}
+	cmd := exec.Command(path)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = out
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("plugin %s failed: %v", path, err)
+	}
+
+	resp := &Response{}
+	if err := json.Unmarshal(out.Bytes(), resp); err != nil {
+		return nil, fmt.Errorf("invalid response of plugin %s: %v", path, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s failed: %s", path, resp.Error)
+	}
+	if err := resp.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid response of plugin %s: %v", path, err)
+	}
+	return resp, nil
+}
+
+// Serve implements a plugin with fn, it reads the request from in and writes
+// the response to out. Errors of fn are returned to kubebuilder in the
+// response. Plugins written in Go call it from their main function:
+//
+//	func main() {
+//		if err := plugin.Serve(os.Stdin, os.Stdout, scaffold); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Serve(in io.Reader, out io.Writer, fn func(*Request) (*Response, error)) error {
+	req := &Request{}
+	if err := json.NewDecoder(in).Decode(req); err != nil {
+		return fmt.Errorf("invalid request: %v", err)
+	}
+
+	var resp *Response
+	if req.APIVersion != APIVersion {
+		resp = &Response{Error: fmt.Sprintf("unsupported protocol version %q, must be %q", req.APIVersion, APIVersion)}
+	} else {
+		var err error
+		if resp, err = fn(req); err != nil {
+			resp = &Response{Error: err.Error()}
+		}
+	}
+	resp.APIVersion = APIVersion
+	return json.NewEncoder(out).Encode(resp)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// TestMain runs the test binary as a plugin when it is called by Call
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_TEST_PLUGIN") == "1" {
+		err := Serve(os.Stdin, os.Stdout, func(req *Request) (*Response, error) {
+			if req.Flags["fail"] == "true" {
+				return nil, fmt.Errorf("failing as asked")
+			}
+			if req.Project == nil {
+				return nil, fmt.Errorf("not in a project")
+			}
+			fmt.Fprintln(os.Stderr, "scaffolding", req.Args[0])
+			return &Response{Files: []File{{
+				Path:    "config/" + req.Args[0] + ".yaml",
+				Content: "domain: " + req.Project.Domain + "\n",
+			}}}, nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCall(t *testing.T) {
+	os.Setenv("KUBEBUILDER_TEST_PLUGIN", "1")
+	defer os.Unsetenv("KUBEBUILDER_TEST_PLUGIN")
+
+	stderr := &bytes.Buffer{}
+	req := &Request{APIVersion: APIVersion, Args: []string{"extra"}}
+	_, err := Call(os.Args[0], req, stderr)
+	if err == nil || !strings.Contains(err.Error(), "not in a project") {
+		t.Errorf("expected the plugin to fail without a project, got %v", err)
+	}
+
+	req.Project = &input.ProjectFile{Domain: "example.com"}
+	resp, err := Call(os.Args[0], req, stderr)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if len(resp.Files) != 1 || resp.Files[0].Path != "config/extra.yaml" || resp.Files[0].Content != "domain: example.com\n" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if stderr.String() != "scaffolding extra\n" {
+		t.Errorf("expected the stderr of the plugin, got %q", stderr.String())
+	}
+
+	req.Flags = map[string]string{"fail": "true"}
+	_, err = Call(os.Args[0], req, stderr)
+	if err == nil || !strings.Contains(err.Error(), "failing as asked") {
+		t.Errorf("expected the error of the plugin, got %v", err)
+	}
+
+	req = &Request{APIVersion: "v0", Project: &input.ProjectFile{Domain: "example.com"}}
+	_, err = Call(os.Args[0], req, stderr)
+	if err == nil || !strings.Contains(err.Error(), `unsupported protocol version "v0"`) {
+		t.Errorf("expected the protocol version to be refused, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		resp Response
+		err  string
+	}{
+		{Response{Files: []File{{Path: "config/a.yaml", IfExists: Merge}}}, ""},
+		{Response{APIVersion: "v2", Files: []File{{Path: "a"}}}, "unsupported protocol version"},
+		{Response{Files: []File{{Path: "/etc/passwd"}}}, "outside of the project"},
+		{Response{Files: []File{{Path: "config/../../a"}}}, "outside of the project"},
+		{Response{Files: []File{{Path: ".kubebuilder/manifest.yaml"}}}, "reserved for kubebuilder"},
+		{Response{Files: []File{{Path: "a", IfExists: "replace"}}}, "unknown ifExists"},
+		{Response{Edits: []Edit{{Path: "main.go"}}}, "exactly one of"},
+		{Response{Edits: []Edit{{Path: "main.go", Import: &Import{Path: "fmt"}, Insert: &Insert{}}}}, "exactly one of"},
+		{Response{Resources: []input.Resource{{Kind: "Frigate"}}}, "must have a group, version and kind"},
+	} {
+		if tc.resp.APIVersion == "" {
+			tc.resp.APIVersion = APIVersion
+		}
+		err := tc.resp.Validate()
+		if tc.err == "" && err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("expected error %q, got %v", tc.err, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, mode := range map[string]os.FileMode{
+		"kubebuilder-foo": 0755,
+		"kubebuilder-bar": 0644,
+		"kubectl-baz":     0755,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, mode); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+
+	oldPath := os.Getenv("PATH")
+	defer os.Setenv("PATH", oldPath)
+	os.Setenv("PATH", filepath.Join(dir, "missing")+string(os.PathListSeparator)+dir)
+
+	if path, err := Lookup("foo"); err != nil || path != filepath.Join(dir, "kubebuilder-foo") {
+		t.Errorf("expected the plugin foo, got %q, %v", path, err)
+	}
+	for _, name := range []string{"bar", "baz", "", "../foo"} {
+		if path, err := Lookup(name); err == nil {
+			t.Errorf("expected no plugin %q, got %s", name, path)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin defines the protocol between kubebuilder and external
+// scaffolding plugins.
+//
+// A plugin is an executable named kubebuilder-<name> on PATH, which is run
+// by `kubebuilder <name> [args] [flags]`. The plugin reads a Request as JSON
+// from its standard input and writes a Response as JSON to its standard
+// output. The files and edits of the response are applied by kubebuilder, so
+// plugins get the same dry-run, merge and manifest tracking as the built-in
+// scaffolding. Anything written to the standard error is shown to the user.
+package plugin
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// APIVersion is the version of the protocol. Plugins must answer with the
+// version of the request, and fail for versions they do not support.
+const APIVersion = "v1alpha1"
+
+// Request is sent to a plugin
+type Request struct {
+	// APIVersion is the version of the protocol
+	APIVersion string `json:"apiVersion"`
+
+	// Args are the positional arguments of the command
+	Args []string `json:"args,omitempty"`
+
+	// Flags are the flags of the command by name, without the leading dashes.
+	// Flags given without a value are "true".
+	Flags map[string]string `json:"flags,omitempty"`
+
+	// Project is the content of the PROJECT file, nil outside of a project
+	Project *input.ProjectFile `json:"project,omitempty"`
+
+	// Boilerplate is the content of hack/boilerplate.go.txt
+	Boilerplate string `json:"boilerplate,omitempty"`
+}
+
+// Response is returned by a plugin
+type Response struct {
+	// APIVersion is the version of the protocol
+	APIVersion string `json:"apiVersion"`
+
+	// Files are the files to write
+	Files []File `json:"files,omitempty"`
+
+	// Edits are the edits of existing files, applied after the files are
+	// written
+	Edits []Edit `json:"edits,omitempty"`
+
+	// Resources are the resources to add to the PROJECT file
+	Resources []input.Resource `json:"resources,omitempty"`
+
+	// Message is printed to the user once the response is applied
+	Message string `json:"message,omitempty"`
+
+	// Error fails the command with the message, nothing is applied
+	Error string `json:"error,omitempty"`
+}
+
+// IfExists is what to do with a file which already exists
+type IfExists string
+
+const (
+	// Error fails if the file exists, the default
+	Error IfExists = "error"
+
+	// Skip keeps the existing file
+	Skip IfExists = "skip"
+
+	// Overwrite replaces the existing file
+	Overwrite IfExists = "overwrite"
+
+	// Merge three-way merges the content into the existing file, see
+	// `kubebuilder alpha rescaffold`
+	Merge IfExists = "merge"
+)
+
+// File is a file to write
+type File struct {
+	// Path is the path of the file relative to the project root, using
+	// forward slashes
+	Path string `json:"path"`
+
+	// Content is the content of the file, written as is
+	Content string `json:"content"`
+
+	// IfExists is what to do if the file already exists
+	IfExists IfExists `json:"ifExists,omitempty"`
+}
+
+// Edit is an edit of an existing file, only one of its edits is set
+type Edit struct {
+	// Path is the path of the file relative to the project root, using
+	// forward slashes
+	Path string `json:"path"`
+
+	// Import adds an import to a Go file
+	Import *Import `json:"import,omitempty"`
+
+	// Insert inserts statements into a Go file
+	Insert *Insert `json:"insert,omitempty"`
+
+	// ListItem adds an item to a list of a kustomization file
+	ListItem *ListItem `json:"listItem,omitempty"`
+}
+
+// Import is the import of a Go package, unless it is already imported
+type Import struct {
+	// Name is the name of the import, empty for the package name
+	Name string `json:"name,omitempty"`
+
+	// Path is the import path
+	Path string `json:"path"`
+}
+
+// Insert inserts Go statements on the line before a marker comment, e.g.
+// // +kubebuilder:scaffold:builder. The statements are not inserted if the
+// block holding the marker has a statement equal to the first one.
+type Insert struct {
+	// Func is the function holding the marker, empty for anywhere in the file
+	Func string `json:"func,omitempty"`
+
+	// Marker is the text of the marker comment
+	Marker string `json:"marker"`
+
+	// Code are the statements to insert
+	Code string `json:"code"`
+}
+
+// ListItem adds an item to a list of a kustomization file, unless the list
+// already has an equal item
+type ListItem struct {
+	// List is the top level field holding the list, e.g. resources
+	List string `json:"list"`
+
+	// Item is the YAML of the item
+	Item string `json:"item"`
+
+	// Commented adds the item commented out
+	Commented bool `json:"commented,omitempty"`
+
+	// Marker is a comment of the list the item is inserted before
+	Marker string `json:"marker,omitempty"`
+}
+
+// Validate returns an error if the response cannot be applied.
+func (r *Response) Validate() error {
+	if r.APIVersion != APIVersion {
+		return fmt.Errorf("unsupported protocol version %q, must be %q", r.APIVersion, APIVersion)
+	}
+	for _, f := range r.Files {
+		if err := validatePath(f.Path); err != nil {
+			return err
+		}
+		switch f.IfExists {
+		case "", Error, Skip, Overwrite, Merge:
+		default:
+			return fmt.Errorf("unknown ifExists %q for %s, must be one of error,skip,overwrite,merge",
+				f.IfExists, f.Path)
+		}
+	}
+	for _, e := range r.Edits {
+		if err := validatePath(e.Path); err != nil {
+			return err
+		}
+		n := 0
+		for _, set := range []bool{e.Import != nil, e.Insert != nil, e.ListItem != nil} {
+			if set {
+				n++
+			}
+		}
+		if n != 1 {
+			return fmt.Errorf("edit of %s must have exactly one of import, insert or listItem", e.Path)
+		}
+	}
+	for _, res := range r.Resources {
+		if res.Group == "" || res.Version == "" || res.Kind == "" {
+			return fmt.Errorf("resource %+v must have a group, version and kind", res)
+		}
+	}
+	return nil
+}
+
+// validatePath returns an error unless p is a path inside the project.
+func validatePath(p string) error {
+	if p == "" {
+		return fmt.Errorf("missing path")
+	}
+	clean := path.Clean(p)
+	if path.IsAbs(clean) || filepath.IsAbs(p) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("path %s is outside of the project", p)
+	}
+	if clean == ".kubebuilder" || strings.HasPrefix(clean, ".kubebuilder/") {
+		return fmt.Errorf("path %s is reserved for kubebuilder", p)
+	}
+	return nil
+}
+
+// Template returns the scaffold file writing f.
+func (f File) Template() input.File {
+	action := input.Error
+	switch f.IfExists {
+	case Skip:
+		action = input.Skip
+	case Overwrite:
+		action = input.Overwrite
+	case Merge:
+		action = input.Merge
+	}
+	return &file{
+		Input: input.Input{
+			Path:           filepath.FromSlash(path.Clean(f.Path)),
+			IfExistsAction: action,
+		},
+		content: []byte(f.Content),
+	}
+}
+
+// file writes the content of a File as is
+type file struct {
+	input.Input
+
+	content []byte
+}
+
+// GetInput implements input.File
+func (f *file) GetInput() (input.Input, error) {
+	return f.Input, nil
+}
+
+// RawContent implements input.Raw
+func (f *file) RawContent() []byte {
+	return f.content
+}
@@ -0,0 +1,136 @@
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/plugin"
+)
+
+var _ = Describe("Applying the response of a plugin", func() {
+	var oldFs afero.Fs
+
+	BeforeEach(func() {
+		oldFs = filesystem.Fs
+		filesystem.Fs = afero.NewMemMapFs()
+		for path, content := range map[string]string{
+			"PROJECT": "version: \"2\"\ndomain: example.com\nrepo: example.com/project\n",
+			"main.go": "package main\n\nfunc main() {\n\t// +kubebuilder:scaffold:builder\n}\n",
+			filepath.Join("config", "default", "kustomization.yaml"): "resources:\n- ../crd\n",
+		} {
+			Expect(afero.WriteFile(filesystem.Fs, path, []byte(content), 0600)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		filesystem.Fs = oldFs
+	})
+
+	It("should write the files, apply the edits and add the resources", func() {
+		resp := &plugin.Response{
+			APIVersion: plugin.APIVersion,
+			Files: []plugin.File{
+				{Path: "config/extra/extra.yaml", Content: "name: {{ not a template }}\n"},
+			},
+			Edits: []plugin.Edit{
+				{Path: "main.go", Import: &plugin.Import{Path: "fmt"}},
+				{Path: "main.go", Insert: &plugin.Insert{
+					Func: "main", Marker: "// +kubebuilder:scaffold:builder", Code: `fmt.Println("extra")`,
+				}},
+				{Path: "config/default/kustomization.yaml", ListItem: &plugin.ListItem{
+					List: "resources", Item: "../extra",
+				}},
+			},
+			Resources: []input.Resource{{
+				Group: "ship", Version: "v1", Kind: "Frigate", Plural: "frigates",
+				API: &input.ResourceAPI{Namespaced: true}, Controller: true,
+			}},
+		}
+		conflicts, err := scaffold.ApplyPlugin(resp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+
+		b, err := afero.ReadFile(filesystem.Fs, filepath.Join("config", "extra", "extra.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("name: {{ not a template }}\n"))
+
+		b, err = afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("extra")
+	// +kubebuilder:scaffold:builder
+}
+`))
+
+		b, err = afero.ReadFile(filesystem.Fs, filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("resources:\n- ../crd\n- ../extra\n"))
+
+		p, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Resources).To(Equal(resp.Resources))
+
+		m, err := manifest.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Get("config/extra/extra.yaml")).NotTo(BeNil())
+
+		// applying the response again changes nothing
+		_, err = scaffold.ApplyPlugin(&plugin.Response{
+			APIVersion: plugin.APIVersion,
+			Edits:      resp.Edits,
+			Resources:  resp.Resources,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		b, err = afero.ReadFile(filesystem.Fs, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("fmt.Println(\"extra\")\n\t// +kubebuilder"))
+		p, err = scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Resources).To(HaveLen(1))
+	})
+
+	It("should write the content of the files as is, without user templates or formatting", func() {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT",
+			[]byte("version: \"2\"\ndomain: example.com\nrepo: example.com/project\ntemplates: templates\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(filesystem.Fs, filepath.Join("templates", "plugin", "file.tmpl"),
+			[]byte("overridden\n"), 0600)).To(Succeed())
+
+		content := "package extra\n\nimport \"fmt\"\n\nvar  x = {{ .Content }}\n"
+		_, err := scaffold.ApplyPlugin(&plugin.Response{
+			APIVersion: plugin.APIVersion,
+			Files:      []plugin.File{{Path: "extra/extra.go", Content: content}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		b, err := afero.ReadFile(filesystem.Fs, filepath.Join("extra", "extra.go"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(content))
+	})
+
+	It("should fail for files which already exist", func() {
+		_, err := scaffold.ApplyPlugin(&plugin.Response{
+			APIVersion: plugin.APIVersion,
+			Files:      []plugin.File{{Path: "main.go", Content: "package main\n"}},
+		})
+		Expect(err).To(MatchError("main.go already exists"))
+	})
+
+	It("should refuse files outside of the project", func() {
+		_, err := scaffold.ApplyPlugin(&plugin.Response{
+			APIVersion: plugin.APIVersion,
+			Files:      []plugin.File{{Path: "../main.go", Content: "package main\n"}},
+		})
+		Expect(err).To(MatchError("path ../main.go is outside of the project"))
+	})
+})
@@ -17,11 +17,8 @@ limitations under the License.
 package scaffold
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"os/exec"
-	"strings"
 
 	"sigs.k8s.io/kubebuilder/cmd/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -46,11 +43,21 @@ type V1Project struct {
 	Project     project.Project
 	Boilerplate project.Boilerplate
 
+	// Image is the controller manager image, controller:latest if empty
+	Image string
+
+	// NamePrefix is the prefix of the names and namespace of the resources,
+	// the name of the project directory if empty
+	NamePrefix string
+
 	DepArgs          []string
 	DefinitelyEnsure *bool
 }
 
 func (p *V1Project) Validate() error {
+	if p.Project.MultiGroup {
+		return fmt.Errorf("the multi group layout is only supported by project version %s", project.Version2)
+	}
 	_, err := exec.LookPath("dep")
 	if err != nil {
 		return fmt.Errorf("dep is not installed (%v). Follow steps at: https://golang.github.io/dep/docs/installation.html", err)
@@ -60,10 +67,9 @@ func (p *V1Project) Validate() error {
 
 func (p *V1Project) EnsureDependencies() (bool, error) {
 	if p.DefinitelyEnsure == nil {
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Println("Run `dep ensure` to fetch dependencies (Recommended) [y/n]?")
-		if !util.Yesno(reader) {
-			return false, nil
+		ensure, err := util.Ask("Run `dep ensure` to fetch dependencies (Recommended)", "fetch-deps")
+		if err != nil || !ensure {
+			return false, err
 		}
 	} else if !*p.DefinitelyEnsure {
 		return false, nil
@@ -71,10 +77,7 @@ func (p *V1Project) EnsureDependencies() (bool, error) {
 
 	c := exec.Command("dep", "ensure") // #nosec
 	c.Args = append(c.Args, p.DepArgs...)
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	return true, c.Run()
+	return true, util.RunCommand(c)
 }
 
 func (p *V1Project) Scaffold() error {
@@ -104,12 +107,18 @@ func (p *V1Project) Scaffold() error {
 		return err
 	}
 
-	// default controller manager image name
-	imgName := "controller:latest"
-
 	s = &Scaffold{}
 	return s.Execute(
 		input.Options{ProjectPath: projectInput.Path, BoilerplatePath: bpInput.Path},
+		p.files()...)
+}
+
+// files are the files of a new version 1 project, in addition to PROJECT
+// and the boilerplate.
+func (p *V1Project) files() []input.File {
+	imgName := imageName(p.Image)
+
+	return []input.File{
 		&project.GitIgnore{},
 		&project.KustomizeRBAC{},
 		&scaffoldv1.KustomizeImagePatch{},
@@ -122,17 +131,25 @@ func (p *V1Project) Scaffold() error {
 		&project.Makefile{Image: imgName},
 		&project.GopkgToml{},
 		&manager.Dockerfile{},
-		&project.Kustomize{},
+		&project.Kustomize{Prefix: p.NamePrefix},
 		&project.KustomizeManager{},
 		&manager.APIs{},
 		&manager.Controller{},
 		&manager.Webhook{},
-		&manager.Cmd{})
+		&manager.Cmd{},
+	}
 }
 
 type V2Project struct {
 	Project     project.Project
 	Boilerplate project.Boilerplate
+
+	// Image is the controller manager image, controller:latest if empty
+	Image string
+
+	// NamePrefix is the prefix of the names and namespace of the resources,
+	// the name of the project directory if empty
+	NamePrefix string
 }
 
 func (p *V2Project) Validate() error {
@@ -141,14 +158,15 @@ func (p *V2Project) Validate() error {
 
 func (p *V2Project) EnsureDependencies() (bool, error) {
 	c := exec.Command("go", "mod", "tidy") // #nosec
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	return true, c.Run()
+	return true, util.RunCommand(c)
 }
 
 func (p *V2Project) Scaffold() error {
 	p.Project.Version = project.Version2
+	if imageName(p.Image) != imageName("") {
+		// rescaffold renders the files with the image again
+		p.Project.Image = p.Image
+	}
 
 	s := &Scaffold{
 		BoilerplateOptional: true,
@@ -174,8 +192,7 @@ func (p *V2Project) Scaffold() error {
 		return err
 	}
 
-	// default controller manager image name
-	imgName := "controller:latest"
+	imgName := imageName(p.Image)
 
 	s = &Scaffold{}
 	return s.Execute(
@@ -192,7 +209,7 @@ func (p *V2Project) Scaffold() error {
 		&scaffoldv2.GoMod{},
 		&scaffoldv2.Makefile{Image: imgName},
 		&scaffoldv2.Dockerfile{},
-		&scaffoldv2.Kustomize{},
+		&scaffoldv2.Kustomize{Prefix: p.NamePrefix},
 		&scaffoldv2.ManagerWebhookPatch{},
 		&scaffoldv2.ManagerRoleBinding{},
 		&scaffoldv2.LeaderElectionRole{},
@@ -207,3 +224,38 @@ func (p *V2Project) Scaffold() error {
 		&certmanager.Kustomization{},
 		&certmanager.KustomizeConfig{})
 }
+
+// Rescaffold renders the project files that are merged with the changes made
+// to them again, picking up the changes of their templates. The image
+// defaults to the one recorded in PROJECT by init. It returns the paths of
+// the files merged with conflicts.
+func (p *V2Project) Rescaffold() ([]string, error) {
+	image := p.Image
+	if image == "" {
+		pf, err := LoadProjectFile("PROJECT")
+		if err != nil {
+			return nil, err
+		}
+		image = pf.Image
+	}
+	imgName := imageName(image)
+
+	merge := input.Input{IfExistsAction: input.Merge}
+	s := &Scaffold{}
+	err := s.Execute(
+		input.Options{},
+		&managerv2.Config{Input: merge, Image: imgName},
+		&scaffoldv2.Main{Input: merge},
+		&scaffoldv2.Makefile{Input: merge, Image: imgName},
+		&scaffoldv2.Dockerfile{Input: merge})
+	return s.Conflicts, err
+}
+
+// imageName returns the controller manager image name, defaulting to
+// controller:latest.
+func imageName(image string) string {
+	if image == "" {
+		return "controller:latest"
+	}
+	return image
+}
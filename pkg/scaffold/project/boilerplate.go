@@ -19,6 +19,8 @@ package project
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -30,9 +32,14 @@ var _ input.File = &Boilerplate{}
 type Boilerplate struct {
 	input.Input
 
-	// License is the License type to write
+	// License is the License type to write, either the SPDX identifier of
+	// one of Licenses or apache2 or none
 	License string
 
+	// LicenseTemplate is a custom header template, which can use .Owner and
+	// .Year. It overrides License.
+	LicenseTemplate string
+
 	// Owner is the copyright owner - e.g. "The Kubernetes Authors"
 	Owner string
 
@@ -56,13 +63,119 @@ func (c *Boilerplate) GetInput() (input.Input, error) {
 	if c.Year == "" {
 		c.Year = fmt.Sprintf("%v", time.Now().Year())
 	}
-	switch c.License {
+	if c.LicenseTemplate != "" {
+		c.TemplateBody = c.LicenseTemplate
+		return c.Input, nil
+	}
+	body, found := licenseHeader(c.License)
+	if !found {
+		return input.Input{}, unsupportedLicense(c.License)
+	}
+	c.TemplateBody = body
+	return c.Input, nil
+}
+
+// Validate validates the license
+func (c *Boilerplate) Validate() error {
+	if len(c.Boilerplate) > 0 || c.LicenseTemplate != "" {
+		return nil
+	}
+	if _, found := licenseHeader(c.License); !found {
+		return unsupportedLicense(c.License)
+	}
+	return nil
+}
+
+// licenses are the headers of the licenses by SPDX identifier
+var licenses = map[string]string{
+	"Apache-2.0":        apache,
+	"MIT":               spdxHeader("MIT", mit),
+	"BSD-2-Clause":      spdxHeader("BSD-2-Clause", bsd2),
+	"BSD-3-Clause":      spdxHeader("BSD-3-Clause", bsd3),
+	"MPL-2.0":           spdxHeader("MPL-2.0", mpl2),
+	"GPL-2.0-only":      spdxHeader("GPL-2.0-only", gpl("2", false)),
+	"GPL-2.0-or-later":  spdxHeader("GPL-2.0-or-later", gpl("2", true)),
+	"GPL-3.0-only":      spdxHeader("GPL-3.0-only", gpl("3", false)),
+	"GPL-3.0-or-later":  spdxHeader("GPL-3.0-or-later", gpl("3", true)),
+	"LGPL-2.1-only":     spdxHeader("LGPL-2.1-only", lgpl("2.1", false)),
+	"LGPL-2.1-or-later": spdxHeader("LGPL-2.1-or-later", lgpl("2.1", true)),
+	"LGPL-3.0-only":     spdxHeader("LGPL-3.0-only", lgpl("3", false)),
+	"LGPL-3.0-or-later": spdxHeader("LGPL-3.0-or-later", lgpl("3", true)),
+}
+
+// Licenses returns the SPDX identifiers of the licenses with a built-in
+// header, sorted.
+func Licenses() []string {
+	ids := make([]string, 0, len(licenses))
+	for id := range licenses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// licenseHeader returns the header template of a license. SPDX identifiers
+// are case insensitive, and apache2, none and the empty license are kept for
+// the projects scaffolded before.
+func licenseHeader(license string) (string, bool) {
+	switch license {
 	case "", "apache2":
-		c.TemplateBody = apache
+		return apache, true
 	case "none":
-		c.TemplateBody = none
+		return none, true
 	}
-	return c.Input, nil
+	for id, header := range licenses {
+		if strings.EqualFold(id, license) {
+			return header, true
+		}
+	}
+	return "", false
+}
+
+func unsupportedLicense(license string) error {
+	return fmt.Errorf("unsupported license %q, supported licenses are %s, apache2 and none",
+		license, strings.Join(Licenses(), ", "))
+}
+
+// spdxHeader returns the header of the license id with the notice text.
+func spdxHeader(id, text string) string {
+	return `/*
+{{ if .Owner }}Copyright {{ .Year }} {{ .Owner }}.
+{{ end }}
+SPDX-License-Identifier: ` + id + `
+
+` + text + `
+*/`
+}
+
+// gpl returns the notice of the GNU General Public License version, or any
+// later version if orLater is set.
+func gpl(version string, orLater bool) string {
+	return gnuNotice("GNU General Public License", version, orLater)
+}
+
+// lgpl returns the notice of the GNU Lesser General Public License version,
+// or any later version if orLater is set.
+func lgpl(version string, orLater bool) string {
+	return gnuNotice("GNU Lesser General Public License", version, orLater)
+}
+
+func gnuNotice(license, version string, orLater bool) string {
+	terms := fmt.Sprintf("version %s of the License.", version)
+	if orLater {
+		terms = fmt.Sprintf("either version %s of the License, or\n(at your option) any later version.", version)
+	}
+	return fmt.Sprintf(`This program is free software: you can redistribute it and/or modify
+it under the terms of the %[1]s as published by
+the Free Software Foundation, %[2]s
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+%[1]s for more details.
+
+You should have received a copy of the %[1]s
+along with this program.  If not, see <https://www.gnu.org/licenses/>.`, license, terms)
 }
 
 var apache = `/*
@@ -84,3 +197,57 @@ limitations under the License.
 var none = `/*
 {{ if .Owner }}Copyright {{ .Year }} {{ .Owner }}{{ end }}.
 */`
+
+var mit = `Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.`
+
+var bsdConditions = `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+`
+
+var bsdDisclaimer = `
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.`
+
+var bsd2 = bsdConditions + bsdDisclaimer
+
+var bsd3 = bsdConditions + `
+3. Neither the name of the copyright holder nor the names of its
+   contributors may be used to endorse or promote products derived from
+   this software without specific prior written permission.
+` + bsdDisclaimer
+
+var mpl2 = `This Source Code Form is subject to the terms of the Mozilla Public
+License, v. 2.0. If a copy of the MPL was not distributed with this
+file, You can obtain one at https://mozilla.org/MPL/2.0/.`
@@ -20,9 +20,12 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
-	"io/ioutil"
+	"strconv"
 	"strings"
 
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
@@ -74,7 +77,7 @@ func (g *GopkgToml) GetInput() (input.Input, error) {
 	}
 
 	// Set the user owned content from the last Gopkg.toml file - e.g. everything before the header
-	lastBytes, err := ioutil.ReadFile(g.Path)
+	lastBytes, err := afero.ReadFile(filesystem.Fs, g.Path)
 	if err != nil {
 		g.UserContent = g.DefaultUserContent
 	} else if g.UserContent, err = g.getUserContent(lastBytes); err != nil {
@@ -158,3 +161,168 @@ name = "gopkg.in/fsnotify.v1"
 source = "https://github.com/fsnotify/fsnotify.git"
 version="v1.4.7"
 `
+
+// GopkgTable is a table of a Gopkg.toml or Gopkg.lock file, e.g. a
+// [[constraint]] with its keys.
+type GopkgTable struct {
+	// Name is the name of the table, e.g. constraint, empty for the keys
+	// before the first table
+	Name string
+
+	// Values are the values of the keys of the table. Strings are unquoted,
+	// other values are kept as written, except inline tables which are
+	// skipped.
+	Values map[string]string
+
+	// Lists are the strings of the array values of the table
+	Lists map[string][]string
+}
+
+// UserGopkgContent returns the part of the Gopkg.toml content b owned by the
+// user, which is all of it if the file is unmanaged.
+func UserGopkgContent(b []byte) []byte {
+	if i := bytes.Index(b, []byte(DefaultGopkgHeader)); i >= 0 {
+		return b[:i]
+	}
+	return b
+}
+
+// ParseGopkg parses the content of a Gopkg.toml or Gopkg.lock file of dep,
+// which use a subset of TOML: tables and arrays of tables of keys with
+// string, array, boolean and number values.
+func ParseGopkg(b []byte) ([]GopkgTable, error) {
+	tables := []GopkgTable{{Values: map[string]string{}, Lists: map[string][]string{}}}
+	lines := strings.Split(string(b), "\n")
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimSpace(stripTomlComment(lines[i]))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "["):
+			// [table] or [[array of tables]]
+			start, end := "[", "]"
+			if strings.HasPrefix(line, "[[") {
+				start, end = "[[", "]]"
+			}
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, start), end))
+			if !strings.HasSuffix(line, end) || name == "" || strings.ContainsAny(name, "[]") {
+				return nil, fmt.Errorf("line %d: invalid table %q", n, line)
+			}
+			tables = append(tables, GopkgTable{Name: name, Values: map[string]string{}, Lists: map[string][]string{}})
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", n, line)
+		}
+		key := strings.Trim(strings.TrimSpace(line[:eq]), `"`)
+		value := strings.TrimSpace(line[eq+1:])
+		// arrays and inline tables may span lines
+		for isOpen(value) {
+			if i+1 >= len(lines) {
+				return nil, fmt.Errorf("line %d: unterminated value of %s", n, key)
+			}
+			i++
+			value += "\n" + strings.TrimSpace(stripTomlComment(lines[i]))
+		}
+
+		t := &tables[len(tables)-1]
+		switch {
+		case strings.HasPrefix(value, "["):
+			t.Lists[key] = tomlStrings(value)
+		case strings.HasPrefix(value, "{"):
+		case strings.HasPrefix(value, `"`):
+			s, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string %s", n, value)
+			}
+			t.Values[key] = s
+		case strings.HasPrefix(value, "'"):
+			t.Values[key] = strings.Trim(value, "'")
+		default:
+			t.Values[key] = value
+		}
+	}
+	if len(tables[0].Values) == 0 && len(tables[0].Lists) == 0 {
+		tables = tables[1:]
+	}
+	return tables, nil
+}
+
+// stripTomlComment returns line without its comment, a # outside of strings.
+func stripTomlComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0 && c == quote && (quote == '\'' || !escaped(line, i)):
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// escaped returns true if the byte of s at i is escaped by a backslash.
+func escaped(s string, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// isOpen returns true if the array or inline table starting value is not
+// closed, the brackets of strings are not counted.
+func isOpen(value string) bool {
+	if !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		return false
+	}
+	depth := 0
+	var quote rune
+	for i, c := range value {
+		switch {
+		case quote != 0 && c == quote && (quote == '\'' || !escaped(value, i)):
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+// tomlStrings returns the strings of the array value.
+func tomlStrings(value string) []string {
+	var values []string
+	for i := 0; i < len(value); i++ {
+		quote := value[i]
+		if quote != '"' && quote != '\'' {
+			continue
+		}
+		j := i + 1
+		for j < len(value) && (value[j] != quote || (quote == '"' && escaped(value, j))) {
+			j++
+		}
+		s := value[i : j+1]
+		if quote == '"' {
+			if unquoted, err := strconv.Unquote(s); err == nil {
+				s = unquoted
+			}
+		} else {
+			s = strings.Trim(s, "'")
+		}
+		values = append(values, s)
+		i = j
+	}
+	return values
+}
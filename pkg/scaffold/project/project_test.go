@@ -77,6 +77,67 @@ Copyright %s Example Owners.
 			})
 		})
 
+		Context("for an SPDX identifier", func() {
+			It("should write the header of the license", func() {
+				instance := &project.Boilerplate{Year: year, License: "MIT", Owner: "Example Owners"}
+				Expect(s.Execute(input.Options{}, instance)).NotTo(HaveOccurred())
+				Expect(result.Actual.String()).To(HavePrefix(fmt.Sprintf(`/*
+Copyright %s Example Owners.
+
+SPDX-License-Identifier: MIT
+
+Permission is hereby granted, free of charge,`, year)))
+				Expect(result.Actual.String()).To(HaveSuffix("THE SOFTWARE.\n*/"))
+			})
+
+			It("should ignore the case of the identifier", func() {
+				instance := &project.Boilerplate{Year: year, License: "gpl-3.0-or-later"}
+				Expect(s.Execute(input.Options{}, instance)).NotTo(HaveOccurred())
+				Expect(result.Actual.String()).To(HavePrefix(`/*
+
+SPDX-License-Identifier: GPL-3.0-or-later
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+`))
+			})
+
+			It("should write the apache2 boilerplate for Apache-2.0", func() {
+				instance := &project.Boilerplate{Year: year, License: "Apache-2.0", Owner: "Example Owners"}
+				Expect(s.Execute(input.Options{}, instance)).NotTo(HaveOccurred())
+				Expect(result.Actual.String()).To(HavePrefix(fmt.Sprintf(`/*
+Copyright %s Example Owners.
+
+Licensed under the Apache License, Version 2.0 (the "License");`, year)))
+			})
+		})
+
+		Context("for an unknown license", func() {
+			It("should list the supported licenses", func() {
+				instance := &project.Boilerplate{License: "WTFPL"}
+				err := s.Execute(input.Options{}, instance)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(`unsupported license "WTFPL", supported licenses are ` +
+					strings.Join(project.Licenses(), ", ") + ", apache2 and none"))
+				Expect(project.Licenses()).To(ContainElement("BSD-3-Clause"))
+			})
+		})
+
+		Context("for a license template", func() {
+			It("should render the template with the owner and the year", func() {
+				instance := &project.Boilerplate{
+					Year:            "2019",
+					License:         "WTFPL",
+					LicenseTemplate: "// Copyright {{.Year}} {{.Owner}}. All rights reserved.",
+					Owner:           "Example Owners",
+				}
+				Expect(s.Execute(input.Options{}, instance)).NotTo(HaveOccurred())
+				Expect(result.Actual.String()).To(BeEquivalentTo("// Copyright 2019 Example Owners. All rights reserved."))
+			})
+		})
+
 		Context("if the boilerplate is given", func() {
 			It("should skip writing Gopkg.toml", func() {
 				instance := &project.Boilerplate{}
@@ -172,6 +233,55 @@ Copyright %s Example Owners.
 				Expect(result.Actual.String()).To(BeEquivalentTo(result.Golden))
 			})
 		})
+
+		Context("parsing the user content", func() {
+			It("should return its tables", func() {
+				tables, err := project.ParseGopkg([]byte(`required = [
+  "github.com/onsi/ginkgo", # for tests
+  "github.com/onsi/gomega",
+]
+
+[[constraint]]
+  name = "github.com/go-logr/logr"
+  version = "0.1.0"
+
+[[override]]
+  name = "gopkg.in/fsnotify.v1"
+  source = "https://github.com/fsnotify/fsnotify.git"
+
+[prune]
+  go-tests = true
+`))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tables).To(Equal([]project.GopkgTable{
+					{
+						Values: map[string]string{},
+						Lists:  map[string][]string{"required": {"github.com/onsi/ginkgo", "github.com/onsi/gomega"}},
+					},
+					{
+						Name:   "constraint",
+						Values: map[string]string{"name": "github.com/go-logr/logr", "version": "0.1.0"},
+						Lists:  map[string][]string{},
+					},
+					{
+						Name: "override",
+						Values: map[string]string{
+							"name":   "gopkg.in/fsnotify.v1",
+							"source": "https://github.com/fsnotify/fsnotify.git",
+						},
+						Lists: map[string][]string{},
+					},
+					{
+						Name:   "prune",
+						Values: map[string]string{"go-tests": "true"},
+						Lists:  map[string][]string{},
+					},
+				}))
+
+				_, err = project.ParseGopkg([]byte("[[constraint]\n"))
+				Expect(err).To(MatchError(`line 1: invalid table "[[constraint]"`))
+			})
+		})
 	})
 
 	Describe("scaffolding a Makefile", func() {
@@ -0,0 +1,301 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	resourcev1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+// SchemaType is the type of the value of a field of the PROJECT file
+type SchemaType int
+
+const (
+	// SchemaString is a scalar read as string
+	SchemaString SchemaType = iota
+	// SchemaBool is a boolean
+	SchemaBool
+	// SchemaMapping is a mapping of the Fields of the field
+	SchemaMapping
+	// SchemaList is a list of mappings of the Fields of the field
+	SchemaList
+)
+
+// SchemaField is the schema of a field of the PROJECT file.
+type SchemaField struct {
+	Type SchemaType
+
+	// Fields are the fields of a mapping, or of the mappings of a list
+	Fields map[string]SchemaField
+}
+
+// FieldError is an invalid field of a PROJECT file.
+type FieldError struct {
+	// Field is the path of the field, e.g. resources[0].kind
+	Field string
+
+	// Message describes what is wrong with the field
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ProjectFileError lists the invalid fields of a PROJECT file.
+type ProjectFileError struct {
+	Path   string
+	Fields []FieldError
+}
+
+func (e *ProjectFileError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Path, strings.Join(msgs, "; "))
+}
+
+// ProjectSchemaV1 are the fields of the PROJECT file of version 1 projects,
+// which are the fields of all versions
+var ProjectSchemaV1 = map[string]SchemaField{
+	"version":   {Type: SchemaString},
+	"domain":    {Type: SchemaString},
+	"repo":      {Type: SchemaString},
+	"templates": {Type: SchemaString},
+}
+
+// ProjectSchemaV2 are the fields of the PROJECT file of version 2 projects
+var ProjectSchemaV2 = withFields(ProjectSchemaV1, map[string]SchemaField{
+	"multigroup": {Type: SchemaBool},
+	"image":      {Type: SchemaString},
+	"resources": {Type: SchemaList, Fields: map[string]SchemaField{
+		"group":   {Type: SchemaString},
+		"version": {Type: SchemaString},
+		"kind":    {Type: SchemaString},
+		"plural":  {Type: SchemaString},
+		"api": {Type: SchemaMapping, Fields: map[string]SchemaField{
+			"namespaced": {Type: SchemaBool},
+		}},
+		"core":       {Type: SchemaBool},
+		"external":   {Type: SchemaBool},
+		"controller": {Type: SchemaBool},
+		"webhooks": {Type: SchemaMapping, Fields: map[string]SchemaField{
+			"defaulting": {Type: SchemaBool},
+			"validation": {Type: SchemaBool},
+			"conversion": {Type: SchemaBool},
+		}},
+	}},
+})
+
+// withFields returns the fields of base and of extra.
+func withFields(base, extra map[string]SchemaField) map[string]SchemaField {
+	fields := map[string]SchemaField{}
+	for name, f := range base {
+		fields[name] = f
+	}
+	for name, f := range extra {
+		fields[name] = f
+	}
+	return fields
+}
+
+// LoadProjectFile reads the PROJECT file at path and validates it.
+func LoadProjectFile(path string) (input.ProjectFile, error) {
+	p, err := ParseProjectFile(path)
+	if err != nil {
+		return input.ProjectFile{}, err
+	}
+	if errs := ValidateProjectFile(&p); len(errs) > 0 {
+		return input.ProjectFile{}, &ProjectFileError{Path: path, Fields: errs}
+	}
+	return p, nil
+}
+
+// ParseProjectFile reads the PROJECT file at path. The file must only have
+// the fields of the schema of its project version, with values of their
+// types, but the values are not validated.
+func ParseProjectFile(path string) (input.ProjectFile, error) {
+	in, err := afero.ReadFile(filesystem.Fs, path)
+	if err != nil {
+		return input.ProjectFile{}, err
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(in, &raw); err != nil {
+		return input.ProjectFile{}, fmt.Errorf("invalid %s: %v", path, err)
+	}
+	fields, ok := raw.(map[interface{}]interface{})
+	if !ok && raw != nil {
+		return input.ProjectFile{}, fmt.Errorf("invalid %s: must be a mapping of fields", path)
+	}
+
+	version := projectVersion(fields)
+	l, err := GetLayout(version)
+	if err == errNoLayout {
+		return input.ProjectFile{}, err
+	}
+	if err != nil {
+		return input.ProjectFile{}, &ProjectFileError{Path: path, Fields: []FieldError{{Field: "version", Message: err.Error()}}}
+	}
+	var errs []FieldError
+	checkFields("", fields, l.ProjectSchema, version, &errs)
+	if len(errs) > 0 {
+		return input.ProjectFile{}, &ProjectFileError{Path: path, Fields: errs}
+	}
+
+	p := input.ProjectFile{}
+	if err := yaml.Unmarshal(in, &p); err != nil {
+		return input.ProjectFile{}, fmt.Errorf("invalid %s: %v", path, err)
+	}
+	p.Version = version
+	for i := range p.Resources {
+		r := &p.Resources[i]
+		if r.API == nil && !r.Controller && r.Webhooks == nil {
+			// resources recorded before their options were all scaffolded
+			// with API types, which are namespaced by default
+			r.API = &input.ResourceAPI{Namespaced: true}
+		}
+		if r.Plural == "" {
+			r.Plural = flect.Pluralize(strings.ToLower(r.Kind))
+		}
+	}
+	return p, nil
+}
+
+// ProjectVersion returns the project version of the PROJECT file at path,
+// without validating the rest of the file.
+func ProjectVersion(path string) (string, error) {
+	in, err := afero.ReadFile(filesystem.Fs, path)
+	if err != nil {
+		return "", err
+	}
+	var fields map[interface{}]interface{}
+	if err := yaml.Unmarshal(in, &fields); err != nil {
+		return "", fmt.Errorf("invalid %s: %v", path, err)
+	}
+	return projectVersion(fields), nil
+}
+
+// projectVersion returns the version field of a PROJECT file. Version 1
+// projects were created before the field, so it defaults to version 1.
+func projectVersion(fields map[interface{}]interface{}) string {
+	v, ok := fields["version"]
+	if !ok || v == nil {
+		return project.Version1
+	}
+	return fmt.Sprint(v)
+}
+
+// checkFields adds an error for each field of value which is not in schema,
+// or is not of the type of its schema.
+func checkFields(prefix string, value map[interface{}]interface{}, schema map[string]SchemaField, version string, errs *[]FieldError) {
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, fmt.Sprint(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := prefix + name
+		f, ok := schema[name]
+		if !ok {
+			*errs = append(*errs, FieldError{Field: field,
+				Message: fmt.Sprintf("unknown field for project version %s", version)})
+			continue
+		}
+		v := value[name]
+		if v == nil {
+			continue
+		}
+		switch f.Type {
+		case SchemaString:
+			switch v.(type) {
+			case string, int, float64:
+			default:
+				*errs = append(*errs, FieldError{Field: field, Message: "must be a string"})
+			}
+		case SchemaBool:
+			if _, ok := v.(bool); !ok {
+				*errs = append(*errs, FieldError{Field: field, Message: "must be true or false"})
+			}
+		case SchemaMapping:
+			m, ok := v.(map[interface{}]interface{})
+			if !ok {
+				*errs = append(*errs, FieldError{Field: field, Message: "must be a mapping"})
+				continue
+			}
+			checkFields(field+".", m, f.Fields, version, errs)
+		case SchemaList:
+			items, ok := v.([]interface{})
+			if !ok {
+				*errs = append(*errs, FieldError{Field: field, Message: "must be a list"})
+				continue
+			}
+			for i, item := range items {
+				itemField := fmt.Sprintf("%s[%d]", field, i)
+				m, ok := item.(map[interface{}]interface{})
+				if !ok {
+					*errs = append(*errs, FieldError{Field: itemField, Message: "must be a mapping"})
+					continue
+				}
+				checkFields(itemField+".", m, f.Fields, version, errs)
+			}
+		}
+	}
+}
+
+// ValidateProjectFile returns the fields of p with invalid values.
+func ValidateProjectFile(p *input.ProjectFile) []FieldError {
+	var errs []FieldError
+	if p.Repo == "" {
+		errs = append(errs, FieldError{Field: "repo", Message: "must be set to the Go import path of the project"})
+	}
+	if p.Domain == "" {
+		errs = append(errs, FieldError{Field: "domain", Message: "must be set to the domain of the API groups, e.g. my.domain"})
+	}
+	seen := map[string]int{}
+	for i, res := range p.Resources {
+		field := fmt.Sprintf("resources[%d]", i)
+		r := &resourcev1.Resource{Group: res.Group, Version: res.Version, Kind: res.Kind}
+		if err := r.Validate(); err != nil {
+			errs = append(errs, FieldError{Field: field, Message: err.Error()})
+			continue
+		}
+		key := res.Group + "/" + res.Version + "/" + res.Kind
+		if j, dup := seen[key]; dup {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("duplicate of resources[%d]", j)})
+			continue
+		}
+		seen[key] = i
+		switch {
+		case res.API != nil && (res.Core || res.External):
+			errs = append(errs, FieldError{Field: field, Message: "core and external are only for resources without api"})
+		case res.Core && res.External:
+			errs = append(errs, FieldError{Field: field, Message: "core and external exclude each other"})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,167 @@
+package scaffold_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ = Describe("PROJECT files", func() {
+	var oldFs afero.Fs
+
+	BeforeEach(func() {
+		oldFs = filesystem.Fs
+		filesystem.Fs = afero.NewMemMapFs()
+	})
+
+	AfterEach(func() {
+		filesystem.Fs = oldFs
+	})
+
+	writeProject := func(content string) {
+		Expect(afero.WriteFile(filesystem.Fs, "PROJECT", []byte(content), 0600)).To(Succeed())
+	}
+
+	It("should load a PROJECT file of its version's schema", func() {
+		writeProject(`version: "2"
+domain: example.com
+repo: example.com/project
+resources:
+- group: ship
+  version: v1
+  kind: Frigate
+`)
+		p, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		// the resources recorded without options have API types
+		Expect(p.Resources).To(Equal([]input.Resource{{
+			Group: "ship", Version: "v1", Kind: "Frigate", Plural: "frigates",
+			API: &input.ResourceAPI{Namespaced: true},
+		}}))
+
+		writeProject("domain: example.com\nrepo: example.com/project\n")
+		p, err = scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Version).To(Equal("1"))
+	})
+
+	It("should name the unknown fields and the fields of the wrong type", func() {
+		writeProject(`version: "2"
+domian: example.com
+repo: example.com/project
+multigroup: "yes"
+resources:
+- group: ship
+  version: v1
+  kind: Frigate
+  api:
+    namespaced: true
+    scope: Cluster
+- kind
+`)
+		_, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).To(MatchError("invalid PROJECT: " +
+			"domian: unknown field for project version 2; " +
+			"multigroup: must be true or false; " +
+			"resources[0].api.scope: unknown field for project version 2; " +
+			"resources[1]: must be a mapping"))
+
+		writeProject(`version: "1"
+domain: example.com
+repo: example.com/project
+multigroup: true
+`)
+		_, err = scaffold.LoadProjectFile("PROJECT")
+		Expect(err).To(MatchError("invalid PROJECT: multigroup: unknown field for project version 1"))
+	})
+
+	It("should name the fields with invalid values", func() {
+		writeProject(`version: "2"
+repo: example.com/project
+resources:
+- group: ship
+  version: v1
+  kind: frigate
+- group: ship
+  version: v1
+  kind: Destroyer
+- group: ship
+  version: v1
+  kind: Destroyer
+- group: apps
+  version: v1
+  kind: Deployment
+  controller: true
+  core: true
+  external: true
+`)
+		_, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).To(MatchError("invalid PROJECT: " +
+			"domain: must be set to the domain of the API groups, e.g. my.domain; " +
+			"resources[0]: kind must be camelcase (expected Frigate was frigate); " +
+			"resources[2]: duplicate of resources[1]; " +
+			"resources[3]: core and external exclude each other"))
+	})
+
+	It("should migrate a version 1 PROJECT file with the resources of the project", func() {
+		writeProject(projectV1)
+		for path, content := range map[string]string{
+			filepath.Join("pkg", "apis", "ship", "v1", "frigate_types.go"): `package v1
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Frigate is the Schema for the frigates API
+type Frigate struct{}
+
+// FrigateList contains a list of Frigate
+type FrigateList struct{}
+
+// FrigateSpec is not a kind
+type FrigateSpec struct{}
+`,
+			filepath.Join("pkg", "apis", "ship", "v1", "destroyer_types.go"): `package v1
+
+// +genclient
+// +genclient:nonNamespaced
+
+// Destroyer is the Schema for the destroyers API
+type Destroyer struct{}
+
+// +genclient:nonNamespaced
+
+// DestroyerList contains a list of Destroyer
+type DestroyerList struct{}
+`,
+			filepath.Join("pkg", "apis", "crew", "v1beta1", "captain_types.go"): `package v1beta1
+
+type Captain struct{}
+
+type CaptainList struct{}
+`,
+			filepath.Join("pkg", "controller", "frigate", "frigate_controller.go"): "package frigate\n",
+		} {
+			Expect(afero.WriteFile(filesystem.Fs, path, []byte(content), 0600)).To(Succeed())
+		}
+
+		p, err := scaffold.LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scaffold.MigrateProjectFile(&p, "2")).To(Succeed())
+		Expect(p.Version).To(Equal("2"))
+		Expect(p.MultiGroup).To(BeTrue())
+		Expect(p.Resources).To(Equal([]input.Resource{
+			{Group: "crew", Version: "v1beta1", Kind: "Captain", API: &input.ResourceAPI{Namespaced: true}},
+			{Group: "ship", Version: "v1", Kind: "Destroyer", API: &input.ResourceAPI{}},
+			{Group: "ship", Version: "v1", Kind: "Frigate", API: &input.ResourceAPI{Namespaced: true}, Controller: true},
+		}))
+
+		Expect(scaffold.MigrateProjectFile(&p, "1")).To(MatchError("cannot migrate PROJECT from version 2 to 1"))
+		Expect(scaffold.MigrateProjectFile(&p, "3")).To(MatchError(`unsupported project version "3", supported versions are 1, 2`))
+	})
+})
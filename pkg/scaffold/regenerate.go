@@ -0,0 +1,158 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	resourcev1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+// Regenerate scaffolds the project of the PROJECT file in the current
+// directory again into another directory, with the current templates: the
+// project is initialized and its recorded APIs and webhooks are created with
+// the options they were created with. Diffing the project against it shows
+// what changed since the project was scaffolded.
+type Regenerate struct {
+	// Output is the directory the project is scaffolded into, which must be
+	// empty or not exist
+	Output string
+
+	// boilerplate is the boilerplate of the project
+	boilerplate string
+}
+
+// Regenerate scaffolds the project into Output.
+func (rg *Regenerate) Regenerate() error {
+	p, err := LoadProjectFile("PROJECT")
+	if err != nil {
+		return err
+	}
+	l, err := GetLayout(p.Version)
+	if err != nil {
+		return err
+	}
+	if l.Regenerate == nil {
+		return fmt.Errorf("kubebuilder alpha regenerate is not supported for project version %s", p.Version)
+	}
+
+	if exists, err := afero.Exists(filesystem.Fs, rg.Output); err != nil {
+		return err
+	} else if exists {
+		empty, err := afero.IsEmpty(filesystem.Fs, rg.Output)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return fmt.Errorf("%s is not empty", rg.Output)
+		}
+	}
+
+	// the Go files get the boilerplate of the project, not a new one
+	b, err := getBoilerplate(filepath.Join("hack", "boilerplate.go.txt"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	rg.boilerplate = b
+
+	base := filesystem.Fs
+	filesystem.Fs = afero.NewBasePathFs(base, rg.Output)
+	defer func() { filesystem.Fs = base }()
+
+	// the templates of the project override the built-in ones in the output
+	// too
+	if p.TemplateDir != "" {
+		if err := copyDir(base, p.TemplateDir); err != nil {
+			return err
+		}
+	}
+	return l.Regenerate(rg, &p)
+}
+
+// RegenerateV2 runs init and creates the resources of p with their options.
+func (rg *Regenerate) RegenerateV2(p *input.ProjectFile) error {
+	err := (&V2Project{
+		Project: project.Project{ProjectFile: input.ProjectFile{
+			Version:     p.Version,
+			Domain:      p.Domain,
+			Repo:        p.Repo,
+			TemplateDir: p.TemplateDir,
+			MultiGroup:  p.MultiGroup,
+		}},
+		Boilerplate: project.Boilerplate{Input: input.Input{Boilerplate: rg.boilerplate}},
+		Image:       p.Image,
+	}).Scaffold()
+	if err != nil {
+		return err
+	}
+
+	for _, res := range p.Resources {
+		r := &resourcev1.Resource{
+			Group:      res.Group,
+			Version:    res.Version,
+			Kind:       res.Kind,
+			Resource:   res.Plural,
+			Namespaced: res.API == nil || res.API.Namespaced,
+		}
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		if res.API != nil || res.Controller {
+			api := &API{Resource: r, DoResource: res.API != nil, DoController: res.Controller}
+			if err := api.Scaffold(); err != nil {
+				return err
+			}
+		}
+		if res.Webhooks != nil {
+			wh := &Webhook{
+				Resource:   r,
+				Defaulting: res.Webhooks.Defaulting,
+				Validating: res.Webhooks.Validation,
+				Conversion: res.Webhooks.Conversion,
+			}
+			if err := wh.Scaffold(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyDir copies the directory at path from the filesystem from to the same
+// path in the filesystem of the scaffolding.
+func copyDir(from afero.Fs, path string) error {
+	return afero.Walk(from, path, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		b, err := afero.ReadFile(from, p)
+		if err != nil {
+			return err
+		}
+		if err := filesystem.Fs.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		return afero.WriteFile(filesystem.Fs, p, b, info.Mode())
+	})
+}
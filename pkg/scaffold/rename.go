@@ -0,0 +1,406 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	resourcev1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	resourcev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Rename renames the kind, group or version of a resource of the project in
+// its Go code, file names and manifests.
+type Rename struct {
+	// From is the resource to rename
+	From *resourcev1.Resource
+
+	// To is the new name of the resource, its empty fields are taken from
+	// From
+	To *resourcev1.Resource
+
+	// Moved are the paths of the files moved by Rename, by their new path
+	Moved map[string]string
+
+	project *input.ProjectFile
+}
+
+func (rn *Rename) setDefaults() error {
+	if rn.project == nil {
+		p, err := LoadProjectFile("PROJECT")
+		if err != nil {
+			return err
+		}
+		rn.project = &p
+	}
+	if rn.To.Group == "" {
+		rn.To.Group = rn.From.Group
+	}
+	if rn.To.Version == "" {
+		rn.To.Version = rn.From.Version
+	}
+	if rn.To.Kind == "" {
+		rn.To.Kind = rn.From.Kind
+	}
+	return nil
+}
+
+// Validate validates the resources of the rename against the project.
+func (rn *Rename) Validate() error {
+	if err := rn.setDefaults(); err != nil {
+		return err
+	}
+	l, err := GetLayout(rn.project.Version)
+	if err != nil {
+		return err
+	}
+	if l.RenameAPI == nil {
+		return fmt.Errorf("kubebuilder alpha rename is not supported for project version %s", rn.project.Version)
+	}
+	if err := rn.From.Validate(); err != nil {
+		return err
+	}
+	if err := rn.To.Validate(); err != nil {
+		return err
+	}
+	if rn.From.Group == rn.To.Group && rn.From.Version == rn.To.Version && rn.From.Kind == rn.To.Kind {
+		return fmt.Errorf("nothing to rename, set the new group, version or kind")
+	}
+
+	p := *rn.project
+	p.Resources = nil
+	found := false
+	for _, r := range rn.project.Resources {
+		if r.Group == rn.From.Group && r.Version == rn.From.Version && r.Kind == rn.From.Kind {
+			found = true
+			continue
+		}
+		if r.Group == rn.To.Group && r.Version == rn.To.Version && r.Kind == rn.To.Kind {
+			return fmt.Errorf("resource %s/%s, Kind=%s already exists in the project", r.Group, r.Version, r.Kind)
+		}
+		p.Resources = append(p.Resources, r)
+	}
+	if !found {
+		return fmt.Errorf("resource %s/%s, Kind=%s is not in the project",
+			rn.From.Group, rn.From.Version, rn.From.Kind)
+	}
+	// the other resources must be in the new group without the multi group
+	// layout
+	return validateResourceGroup(&p, rn.To)
+}
+
+// Rename renames the resource, the files it touches are listed by their
+// changes in the filesystem.
+func (rn *Rename) Rename() error {
+	if err := rn.Validate(); err != nil {
+		return err
+	}
+	l, err := GetLayout(rn.project.Version)
+	if err != nil {
+		return err
+	}
+	return l.RenameAPI(rn, rn.project)
+}
+
+// RenameV2 renames the resource in a project of version 2, it is the
+// RenameAPI of its layout.
+func (rn *Rename) RenameV2(p *input.ProjectFile) error {
+	from, to := rn.From, rn.To
+	rn.Moved = map[string]string{}
+
+	found := -1
+	lastOfVersion, newVersion, sharedCRD := true, true, false
+	for i, res := range p.Resources {
+		switch {
+		case res.Group == from.Group && res.Version == from.Version && res.Kind == from.Kind:
+			found = i
+		case res.API == nil:
+			// only resources with API types have a group version package
+			continue
+		case res.Group == from.Group && res.Version == from.Version:
+			lastOfVersion = false
+		case res.Group == from.Group && res.Kind == from.Kind:
+			// the other versions of the kind share its CRD
+			sharedCRD = true
+		}
+		if res.Group == to.Group && res.Version == to.Version {
+			newVersion = false
+		}
+	}
+	versionChanged := from.Group != to.Group || from.Version != to.Version
+
+	s := &Scaffold{BoilerplateOptional: true}
+	if err := s.defaultOptions(&input.Options{}); err != nil {
+		return err
+	}
+
+	// the plurals are the names of the CRDs, kept if the kind is the same
+	from.Resource = crdv2.Plural(from, p.MultiGroup)
+	to.Resource = from.Resource
+	if to.Kind != from.Kind {
+		to.Resource = flect.Pluralize(strings.ToLower(to.Kind))
+	}
+	rename := resourcev2.NewRename(p, from, to)
+
+	// the files of the resource by their new paths, computed before they
+	// are moved
+	goFiles := map[string]string{}
+	otherFiles := map[string]string{}
+	pairs := []struct {
+		files    map[string]string
+		from, to input.File
+		// crd files are shared by the versions of the kind
+		crd bool
+	}{
+		{goFiles, &resourcev2.Types{Resource: from}, &resourcev2.Types{Resource: to}, false},
+		{goFiles, &webhook.Webhook{Resource: from}, &webhook.Webhook{Resource: to}, false},
+		{goFiles, &resourcev2.Controller{Resource: from}, &resourcev2.Controller{Resource: to}, false},
+		{otherFiles, &resourcev2.CRDSample{Resource: from}, &resourcev2.CRDSample{Resource: to}, false},
+		{otherFiles, &crdv2.EnableWebhookPatch{Resource: from}, &crdv2.EnableWebhookPatch{Resource: to}, true},
+		{otherFiles, &crdv2.EnableCAInjectionPatch{Resource: from}, &crdv2.EnableCAInjectionPatch{Resource: to}, true},
+	}
+	for _, pair := range pairs {
+		if pair.crd && sharedCRD {
+			continue
+		}
+		fromPath, err := s.path(pair.from)
+		if err != nil {
+			return err
+		}
+		toPath, err := s.path(pair.to)
+		if err != nil {
+			return err
+		}
+		if exists(fromPath) {
+			pair.files[fromPath] = toPath
+		}
+	}
+	fromKustomization := &crdv2.Kustomization{Resource: from}
+	toKustomization := &crdv2.Kustomization{Resource: to}
+	for _, f := range []input.File{fromKustomization, toKustomization} {
+		if _, err := s.path(f); err != nil {
+			return err
+		}
+	}
+	if crdPath := fromKustomization.CRDPath(); !sharedCRD && exists(crdPath) {
+		otherFiles[crdPath] = toKustomization.CRDPath()
+	}
+
+	// the CRD keeps its entries with other versions of the kind
+	if !sharedCRD && exists(fromKustomization.Path) {
+		if err := fromKustomization.Rename(toKustomization); err != nil {
+			return fmt.Errorf("error updating kustomization.yaml: %v", err)
+		}
+	}
+
+	goPaths, err := goFilePaths()
+	if err != nil {
+		return err
+	}
+	for _, path := range goPaths {
+		newPath, owned := goFiles[path]
+		if !owned {
+			newPath = path
+		}
+		if _, err := rename.GoFile(path, newPath, owned); err != nil {
+			return fmt.Errorf("error renaming %s in %s: %v", from.Kind, path, err)
+		}
+	}
+	for path, newPath := range otherFiles {
+		if err := rename.File(path, newPath); err != nil {
+			return fmt.Errorf("error renaming %s in %s: %v", from.Kind, path, err)
+		}
+	}
+	for _, files := range []map[string]string{goFiles, otherFiles} {
+		for path, newPath := range files {
+			if filepath.Clean(path) != filepath.Clean(newPath) {
+				rn.Moved[newPath] = path
+			}
+		}
+	}
+
+	if versionChanged {
+		if err := rn.moveVersion(s, p, lastOfVersion, newVersion); err != nil {
+			return err
+		}
+	}
+
+	res := &p.Resources[found]
+	res.Group, res.Version, res.Kind, res.Plural = to.Group, to.Version, to.Kind, to.Resource
+	if err := saveProjectFile("PROJECT", p); err != nil {
+		return fmt.Errorf("error updating project file with resource information: %v", err)
+	}
+	return nil
+}
+
+// moveVersion wires the new group version of the resource, and removes the
+// former group version with its last resource. The deepcopy functions of
+// the resource are left to be generated again in the new group version.
+func (rn *Rename) moveVersion(s *Scaffold, p *input.ProjectFile, lastOfVersion, newVersion bool) error {
+	from, to := rn.From, rn.To
+
+	fromGroup := &resourcev2.Group{Resource: from}
+	fromGroupPath, err := s.path(fromGroup)
+	if err != nil {
+		return err
+	}
+	deepcopyPath := filepath.Join(util.APIDir(from, p.MultiGroup), "zz_generated.deepcopy.go")
+	if lastOfVersion {
+		if err := removeFiles(fromGroupPath, deepcopyPath); err != nil {
+			return err
+		}
+	} else if exists(deepcopyPath) {
+		err := resourcev2.RemoveMethods(deepcopyPath,
+			from.Kind, from.Kind+"List", from.Kind+"Spec", from.Kind+"Status")
+		if err != nil {
+			return fmt.Errorf("error updating %s: %v", deepcopyPath, err)
+		}
+	}
+	if newVersion {
+		err := (&Scaffold{}).Execute(input.Options{}, &resourcev2.Group{Resource: to})
+		if err != nil && !isAlreadyExistsError(err) {
+			return fmt.Errorf("error scaffolding APIs: %v", err)
+		}
+	}
+
+	err = (&resourcev2.Main{}).Update(
+		&resourcev2.MainUpdateOptions{
+			Project:      p,
+			WireResource: true,
+			Resource:     to,
+		})
+	if err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+	err = (&resourcev2.Main{}).Remove(
+		&resourcev2.MainUpdateOptions{
+			Project:      p,
+			WireResource: lastOfVersion,
+			Resource:     from,
+		})
+	if err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+
+	// the suite tests set up the group versions of their controllers
+	toController := &resourcev2.Controller{Resource: to}
+	toControllerPath, err := s.path(toController)
+	if err != nil || !exists(toControllerPath) {
+		return err
+	}
+	toSuite := &resourcev2.ControllerSuiteTest{Resource: to}
+	toSuitePath, err := s.path(toSuite)
+	if err != nil {
+		return err
+	}
+	if !exists(toSuitePath) {
+		if err := (&Scaffold{}).Execute(input.Options{}, toSuite); err != nil {
+			return fmt.Errorf("error scaffolding controller: %v", err)
+		}
+	}
+	if err := toSuite.Update(); err != nil {
+		return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+	}
+
+	fromSuite := &resourcev2.ControllerSuiteTest{Resource: from}
+	fromSuitePath, err := s.path(fromSuite)
+	if err != nil || fromSuitePath == toSuitePath || !exists(fromSuitePath) {
+		return err
+	}
+	controllers, err := afero.Glob(filesystem.Fs, filepath.Join(filepath.Dir(fromSuitePath), "*_controller.go"))
+	if err != nil {
+		return err
+	}
+	if len(controllers) == 0 {
+		return removeFiles(fromSuitePath)
+	}
+	if lastOfVersion {
+		if err := fromSuite.Remove(); err != nil {
+			return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+		}
+	}
+	return nil
+}
+
+// path sets the fields of f and returns its path.
+func (s *Scaffold) path(f input.File) (string, error) {
+	if err := s.setFieldsAndValidate(f); err != nil {
+		return "", err
+	}
+	i, err := f.GetInput()
+	if err != nil {
+		return "", err
+	}
+	return i.Path, nil
+}
+
+// goFilePaths returns the paths of the Go files of the project, sorted.
+// Vendored packages, hidden directories and testdata are skipped.
+func goFilePaths() ([]string, error) {
+	var paths []string
+	err := afero.Walk(filesystem.Fs, ".", func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			// the files removed in an overlay are still listed in their
+			// directory
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != "." && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// removeFiles removes the files at paths which exist.
+func removeFiles(paths ...string) error {
+	var removed []string
+	for _, path := range paths {
+		if err := filesystem.Fs.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		removed = append(removed, path)
+	}
+	return manifest.Removed(removed...)
+}
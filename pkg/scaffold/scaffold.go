@@ -20,17 +20,18 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/spf13/afero"
 	"golang.org/x/tools/imports"
 	yaml "gopkg.in/yaml.v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
-	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
 )
 
 // Scaffold writes Templates to scaffold new files
@@ -51,9 +52,20 @@ type Scaffold struct {
 	// ProjectPath is the relative path to the project root
 	ProjectPath string
 
+	// TemplateDir is the directory with user provided templates overriding
+	// the built-in ones. Defaults to the templates directory of the project.
+	TemplateDir string
+
 	GetWriter func(path string) (io.Writer, error)
 
 	FileExists func(path string) bool
+
+	// Conflicts lists the files merged with conflicts by the Merge action
+	Conflicts []string
+
+	// trackFiles records the written files in the manifest, only done for
+	// files written to the filesystem
+	trackFiles bool
 }
 
 func (s *Scaffold) setFieldsAndValidate(t input.File) error {
@@ -67,6 +79,9 @@ func (s *Scaffold) setFieldsAndValidate(t input.File) error {
 	if b, ok := t.(input.Domain); ok {
 		b.SetDomain(s.Project.Domain)
 	}
+	if b, ok := t.(input.MultiGroup); ok {
+		b.SetMultiGroup(s.Project.MultiGroup)
+	}
 	if b, ok := t.(input.Version); ok {
 		b.SetVersion(s.Project.Version)
 	}
@@ -86,41 +101,26 @@ func (s *Scaffold) setFieldsAndValidate(t input.File) error {
 	return nil
 }
 
-// LoadProjectFile reads the project file and deserializes it into a Project
-func LoadProjectFile(path string) (input.ProjectFile, error) {
-	in, err := ioutil.ReadFile(path) // nolint: gosec
-	if err != nil {
-		return input.ProjectFile{}, err
-	}
-	p := input.ProjectFile{}
-	err = yaml.Unmarshal(in, &p)
-	if err != nil {
-		return input.ProjectFile{}, err
-	}
-	if p.Version == "" {
-		// older kubebuilder project does not have scaffolding version
-		// specified, so default it to Version1
-		p.Version = project.Version1
-	}
-	return p, nil
-}
-
 // saveProjectFile saves the given ProjectFile at the given path.
 func saveProjectFile(path string, project *input.ProjectFile) error {
 	content, err := yaml.Marshal(project)
 	if err != nil {
 		return fmt.Errorf("error marshalling project info %v", err)
 	}
-	err = ioutil.WriteFile(path, content, os.ModePerm)
+	before, err := afero.ReadFile(filesystem.Fs, path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read project file at %s %v", path, err)
+	}
+	err = afero.WriteFile(filesystem.Fs, path, content, os.ModePerm)
 	if err != nil {
 		return fmt.Errorf("failed to save project file at %s %v", path, err)
 	}
-	return nil
+	return manifest.Edited(path, before, content)
 }
 
 // GetBoilerplate reads the boilerplate file
 func getBoilerplate(path string) (string, error) {
-	b, err := ioutil.ReadFile(path) // nolint: gosec
+	b, err := afero.ReadFile(filesystem.Fs, path)
 	return string(b), err
 }
 
@@ -148,17 +148,22 @@ func (s *Scaffold) defaultOptions(options *input.Options) error {
 		return err
 	}
 
+	if s.TemplateDir == "" {
+		s.TemplateDir = s.Project.TemplateDir
+	}
+
 	return nil
 }
 
 // Execute executes scaffolding the Files
 func (s *Scaffold) Execute(options input.Options, files ...input.File) error {
 	if s.GetWriter == nil {
-		s.GetWriter = (&FileWriter{}).WriteCloser
+		s.GetWriter = (&FileWriter{Fs: filesystem.Fs}).WriteCloser
+		s.trackFiles = true
 	}
 	if s.FileExists == nil {
 		s.FileExists = func(path string) bool {
-			_, err := os.Stat(path)
+			_, err := filesystem.Fs.Stat(path)
 			return err == nil
 		}
 	}
@@ -187,6 +192,50 @@ func isAlreadyExistsError(e error) bool {
 	return ok
 }
 
+// IsAlreadyExists returns true if err is caused by a file which is not
+// scaffolded because it already exists.
+func IsAlreadyExists(err error) bool {
+	return causedBy(err, isAlreadyExistsError)
+}
+
+// IsMarkerMissing returns true if err is caused by a scaffold marker which
+// cannot be found in the file code is inserted in.
+func IsMarkerMissing(err error) bool {
+	return causedBy(err, scaffoldv2.IsInsertionPointError)
+}
+
+// causedBy returns true if is returns true for err or for the error it
+// happened in the context of.
+func causedBy(err error, is func(error) bool) bool {
+	for err != nil {
+		if is(err) {
+			return true
+		}
+		c, ok := err.(*contextError)
+		if !ok {
+			return false
+		}
+		err = c.err
+	}
+	return false
+}
+
+// contextError is an error with the context it happened in, which keeps the
+// error for IsAlreadyExists and IsMarkerMissing
+type contextError struct {
+	context string
+	err     error
+}
+
+func (e *contextError) Error() string {
+	return fmt.Sprintf("%s: %v", e.context, e.err)
+}
+
+// withContext returns err with the context it happened in.
+func withContext(context string, err error) error {
+	return &contextError{context: context, err: err}
+}
+
 // doFile scaffolds a single file
 func (s *Scaffold) doFile(e input.File) error {
 	// Set common fields
@@ -201,10 +250,23 @@ func (s *Scaffold) doFile(e input.File) error {
 		return err
 	}
 
+	raw, isRaw := e.(input.Raw)
+	if !isRaw {
+		// Replace the built-in template with the user provided one
+		body, err := s.userTemplate(e)
+		if err != nil {
+			return err
+		}
+		if body != "" {
+			i.TemplateBody = body
+		}
+	}
+
 	// Check if the file to write already exists
-	if s.FileExists(i.Path) {
+	exists := s.FileExists(i.Path)
+	if exists {
 		switch i.IfExistsAction {
-		case input.Overwrite:
+		case input.Overwrite, input.Merge:
 		case input.Skip:
 			return nil
 		case input.Error:
@@ -212,34 +274,54 @@ func (s *Scaffold) doFile(e input.File) error {
 		}
 	}
 
-	if err := s.doTemplate(i, e); err != nil {
+	var b []byte
+	if isRaw {
+		b = raw.RawContent()
+	} else if b, err = s.doTemplate(i, e); err != nil {
 		return err
 	}
-	return nil
+
+	var current []byte
+	if i.IfExistsAction == input.Merge || i.Mergeable {
+		rendered := b
+		if i.IfExistsAction == input.Merge && exists {
+			if current, err = afero.ReadFile(filesystem.Fs, i.Path); err != nil {
+				return fmt.Errorf("failed to read %s: %v", i.Path, err)
+			}
+			if b, err = s.merge(i.Path, current, rendered); err != nil {
+				return err
+			}
+		}
+		if err := saveMergeBase(i.Path, rendered); err != nil {
+			return err
+		}
+	}
+
+	if err := s.write(i.Path, b); err != nil {
+		return err
+	}
+
+	if !s.trackFiles {
+		return nil
+	}
+	if current != nil {
+		// merged files keep the changes made to them
+		return manifest.Edited(i.Path, current, b)
+	}
+	return manifest.Record(i.Path, TemplateKey(e), b)
 }
 
 // doTemplate executes the template for a file using the input
-func (s *Scaffold) doTemplate(i input.Input, e input.File) error {
+func (s *Scaffold) doTemplate(i input.Input, e input.File) ([]byte, error) {
 	temp, err := newTemplate(e).Parse(i.TemplateBody)
 	if err != nil {
-		return err
-	}
-	f, err := s.GetWriter(i.Path)
-	if err != nil {
-		return err
-	}
-	if c, ok := f.(io.Closer); ok {
-		defer func() {
-			if err := c.Close(); err != nil {
-				log.Fatal(err)
-			}
-		}()
+		return nil, err
 	}
 
 	out := &bytes.Buffer{}
 	err = temp.Execute(out, e)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	b := out.Bytes()
 
@@ -248,9 +330,25 @@ func (s *Scaffold) doTemplate(i input.Input, e input.File) error {
 		b, err = imports.Process(i.Path, b, nil)
 		if err != nil {
 			fmt.Printf("%s\n", out.Bytes())
-			return err
+			return nil, err
 		}
 	}
+	return b, nil
+}
+
+// write writes the content of a file using the writer for its path
+func (s *Scaffold) write(path string, b []byte) (err error) {
+	f, err := s.GetWriter(path)
+	if err != nil {
+		return err
+	}
+	if c, ok := f.(io.Closer); ok {
+		defer func() {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}()
+	}
 
 	_, err = f.Write(b)
 	return err
@@ -5,6 +5,9 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v1" // register the layouts
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v2"
 )
 
 func TestScaffold(t *testing.T) {
@@ -30,6 +30,8 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v1" // register the layouts
+	_ "sigs.k8s.io/kubebuilder/pkg/scaffold/layout/v2"
 )
 
 // TestResult is the result of running the scaffolding.
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// templateKeyPrefix is trimmed from the package path of a scaffold type to
+// build its template key
+const templateKeyPrefix = "sigs.k8s.io/kubebuilder/pkg/scaffold/"
+
+// TemplateExt is the file extension of user provided templates
+const TemplateExt = ".tmpl"
+
+// TemplateKey returns the key identifying the template of a scaffold file,
+// which is its package path relative to pkg/scaffold and its type name, e.g.
+// v2/Controller or v2/crd/Kustomization.
+func TemplateKey(f input.File) string {
+	t := reflect.TypeOf(f)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return path.Join(strings.TrimPrefix(t.PkgPath(), templateKeyPrefix), t.Name())
+}
+
+// TemplatePath returns the path of the template for the file with the given
+// key in the template directory dir.
+func TemplatePath(dir, key string) string {
+	return filepath.Join(dir, filepath.FromSlash(key)+TemplateExt)
+}
+
+// PartKey returns the template key of a part of the template of a file
+// implementing input.TemplateParts, e.g. v2/webhook/Webhook/defaulting.
+func PartKey(f input.File, part input.TemplatePart) string {
+	return path.Join(TemplateKey(f), part.Name)
+}
+
+// userTemplate returns the body of the user provided template for f, or ""
+// if there is none. The template of a file implementing input.TemplateParts
+// is composed of the used parts, each from its user provided template or
+// built-in.
+func (s *Scaffold) userTemplate(f input.File) (string, error) {
+	if s.TemplateDir == "" {
+		return "", nil
+	}
+	parts, ok := f.(input.TemplateParts)
+	if !ok {
+		return s.readUserTemplate(TemplateKey(f))
+	}
+
+	var body string
+	var found bool
+	for _, part := range parts.TemplateParts() {
+		if !part.Used {
+			continue
+		}
+		b, err := s.readUserTemplate(PartKey(f, part))
+		if err != nil {
+			return "", err
+		}
+		if b == "" {
+			b = part.Body
+		} else {
+			found = true
+		}
+		body += b
+	}
+	if !found {
+		return "", nil
+	}
+	return body, nil
+}
+
+// readUserTemplate returns the body of the user provided template with the
+// given key, or "" if there is none.
+func (s *Scaffold) readUserTemplate(key string) (string, error) {
+	p := TemplatePath(s.TemplateDir, key)
+	exists, err := afero.Exists(filesystem.Fs, p)
+	if err != nil || !exists {
+		return "", err
+	}
+	b, err := afero.ReadFile(filesystem.Fs, p)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %v", p, err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templates provides the built-in templates of the scaffold files.
+package templates
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	scaffoldv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1"
+	controllerv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/controller"
+	managerv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/manager"
+	metricsauthv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/metricsauth"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	webhookv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/webhook"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/certmanager"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	managerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+	metricsauthv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/metricsauth"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Builtin returns the built-in template bodies of all scaffold
+// files, keyed by their TemplateKey. The templates composed of parts are
+// returned by part, keyed by their PartKey.
+func Builtin() (map[string]string, error) {
+	// the templates are the same for every resource, use a placeholder to
+	// build the inputs
+	r := &resource.Resource{Group: "group", Version: "v1", Kind: "Kind", Resource: "kinds", Namespaced: true}
+	webhookConfig := webhookv1.Config{Server: "default", Type: "mutating", Operations: []string{"create"}}
+
+	files := []input.File{
+		// common
+		&project.Boilerplate{},
+		&project.GitIgnore{},
+		&project.AuthProxyRole{},
+		&project.AuthProxyRoleBinding{},
+
+		// version 1
+		&project.KustomizeRBAC{},
+		&project.Makefile{},
+		&project.GopkgToml{},
+		&project.Kustomize{Prefix: "project"},
+		&project.KustomizeManager{},
+		&scaffoldv1.KustomizeImagePatch{},
+		&scaffoldv1.AuthProxyService{},
+		&metricsauthv1.KustomizePrometheusMetricsPatch{},
+		&metricsauthv1.KustomizeAuthProxyPatch{},
+		&managerv1.Config{},
+		&managerv1.Dockerfile{},
+		&managerv1.APIs{},
+		&managerv1.Controller{},
+		&managerv1.Webhook{},
+		&managerv1.Cmd{},
+		&resource.Register{Resource: r},
+		&resource.Types{Resource: r},
+		&resource.VersionSuiteTest{Resource: r},
+		&resource.TypesTest{Resource: r},
+		&resource.Doc{Resource: r},
+		&resource.Group{Resource: r},
+		&resource.AddToScheme{Resource: r},
+		&resource.CRDSample{Resource: r},
+		&controllerv1.Controller{Resource: r},
+		&controllerv1.AddController{Resource: r},
+		&controllerv1.Test{Resource: r},
+		&controllerv1.SuiteTest{Resource: r},
+		&webhookv1.AdmissionHandler{Resource: r, Config: webhookConfig},
+		&webhookv1.AdmissionWebhookBuilder{Resource: r, Config: webhookConfig},
+		&webhookv1.AdmissionWebhooks{Resource: r, Config: webhookConfig},
+		&webhookv1.AddAdmissionWebhookBuilderHandler{Resource: r, Config: webhookConfig},
+		&webhookv1.Server{Resource: r, Config: webhookConfig},
+		&webhookv1.AddServer{Resource: r, Config: webhookConfig},
+
+		// version 2
+		&scaffoldv2.KustomizeImagePatch{},
+		&scaffoldv2.AuthProxyService{},
+		&scaffoldv2.Main{},
+		&scaffoldv2.GoMod{},
+		&scaffoldv2.Makefile{},
+		&scaffoldv2.Dockerfile{},
+		&scaffoldv2.Kustomize{Prefix: "project"},
+		&scaffoldv2.ManagerWebhookPatch{},
+		&scaffoldv2.ManagerRoleBinding{},
+		&scaffoldv2.LeaderElectionRole{},
+		&scaffoldv2.LeaderElectionRoleBinding{},
+		&scaffoldv2.KustomizeRBAC{},
+		&scaffoldv2.Types{Resource: r},
+		&scaffoldv2.Group{Resource: r},
+		&scaffoldv2.CRDSample{Resource: r},
+		&scaffoldv2.Controller{Resource: r},
+		&scaffoldv2.ControllerSuiteTest{Resource: r},
+		&metricsauthv2.KustomizePrometheusMetricsPatch{},
+		&metricsauthv2.KustomizeAuthProxyPatch{},
+		&managerv2.Config{},
+		&managerv2.Kustomization{},
+		&crdv2.Kustomization{Resource: r},
+		&crdv2.KustomizeConfig{},
+		&crdv2.EnableWebhookPatch{Resource: r},
+		&crdv2.EnableCAInjectionPatch{Resource: r},
+		&webhookv2.Kustomization{},
+		&webhookv2.KustomizeConfigWebhook{},
+		&webhookv2.Service{},
+		&webhookv2.InjectCAPatch{},
+		&webhookv2.Webhook{Resource: r},
+		&certmanager.CertManager{},
+		&certmanager.Kustomization{},
+		&certmanager.KustomizeConfig{},
+	}
+
+	templates := map[string]string{}
+	for _, f := range files {
+		if parts, ok := f.(input.TemplateParts); ok {
+			for _, part := range parts.TemplateParts() {
+				templates[scaffold.PartKey(f, part)] = part.Body
+			}
+			continue
+		}
+		i, err := f.GetInput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get template for %s: %v", scaffold.TemplateKey(f), err)
+		}
+		templates[scaffold.TemplateKey(f)] = i.TemplateBody
+	}
+	return templates, nil
+}
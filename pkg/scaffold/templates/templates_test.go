@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	templates, err := Builtin()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	for _, key := range []string{"v2/Controller", "v2/crd/Kustomization", "v1/resource/Types", "project/Boilerplate",
+		"v2/webhook/Webhook/base", "v2/webhook/Webhook/defaulting", "v2/webhook/Webhook/validating"} {
+		if _, found := templates[key]; !found {
+			t.Errorf("missing template %s", key)
+		}
+	}
+	if _, found := templates["v2/webhook/Webhook"]; found {
+		t.Errorf("the webhook template is composed of parts, expected no template v2/webhook/Webhook")
+	}
+	for key, body := range templates {
+		if body == "" {
+			t.Errorf("empty template %s", key)
+		}
+	}
+}
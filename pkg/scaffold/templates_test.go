@@ -0,0 +1,87 @@
+package scaffold_test
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var _ = Describe("Templates", func() {
+	Describe("TemplateKey", func() {
+		It("should use the package path relative to pkg/scaffold and the type name", func() {
+			Expect(scaffold.TemplateKey(&scaffoldv2.Main{})).To(Equal("v2/Main"))
+			Expect(scaffold.TemplateKey(&crdv2.Kustomization{})).To(Equal("v2/crd/Kustomization"))
+		})
+	})
+
+	Describe("scaffolding with a template directory", func() {
+		var oldFs afero.Fs
+		var out *bytes.Buffer
+		var s *scaffold.Scaffold
+
+		BeforeEach(func() {
+			oldFs = filesystem.Fs
+			filesystem.Fs = afero.NewMemMapFs()
+			out = &bytes.Buffer{}
+			s = &scaffold.Scaffold{
+				BoilerplateOptional: true,
+				ProjectOptional:     true,
+				TemplateDir:         "templates",
+				GetWriter: func(path string) (io.Writer, error) {
+					return out, nil
+				},
+			}
+		})
+
+		AfterEach(func() {
+			filesystem.Fs = oldFs
+		})
+
+		It("should use the user provided template", func() {
+			path := filepath.Join("templates", "v2", "Dockerfile.tmpl")
+			Expect(afero.WriteFile(filesystem.Fs, path, []byte("FROM scratch # {{ .Repo }}\n"), 0600)).To(Succeed())
+
+			f := &scaffoldv2.Dockerfile{}
+			f.Repo = "example.com/project"
+			Expect(s.Execute(input.Options{}, f)).To(Succeed())
+			Expect(out.String()).To(Equal("FROM scratch # example.com/project\n"))
+		})
+
+		It("should compose the templates of parts from the used parts", func() {
+			path := filepath.Join("templates", "v2", "webhook", "Webhook", "validating.tmpl")
+			Expect(afero.WriteFile(filesystem.Fs, path, []byte("\n// validate {{ .Resource.Kind }}\n"), 0600)).To(Succeed())
+			r := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+
+			f := &webhookv2.Webhook{Resource: r, Defaulting: true}
+			f.Repo, f.Domain = "example.com/project", "example.com"
+			Expect(s.Execute(input.Options{}, f)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("func (r *Frigate) Default()"))
+			Expect(out.String()).NotTo(ContainSubstring("validate"))
+
+			out.Reset()
+			f = &webhookv2.Webhook{Resource: r, Validating: true}
+			f.Repo, f.Domain = "example.com/project", "example.com"
+			Expect(s.Execute(input.Options{}, f)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("SetupWebhookWithManager"))
+			Expect(out.String()).To(ContainSubstring("// validate Frigate\n"))
+			Expect(out.String()).NotTo(ContainSubstring("Default()"))
+		})
+
+		It("should fall back to the built-in template", func() {
+			Expect(s.Execute(input.Options{}, &scaffoldv2.Dockerfile{})).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("FROM golang"))
+		})
+	})
+})
@@ -23,39 +23,52 @@ import (
 	"path/filepath"
 	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
 )
 
+// coreGroups are the domains of the Kubernetes API groups, whose types are
+// used from the k8s.io/api package
+var coreGroups = map[string]string{
+	"apps":                  "",
+	"admission":             "k8s.io",
+	"admissionregistration": "k8s.io",
+	"auditregistration":     "k8s.io",
+	"apiextensions":         "k8s.io",
+	"authentication":        "k8s.io",
+	"authorization":         "k8s.io",
+	"autoscaling":           "",
+	"batch":                 "",
+	"certificates":          "k8s.io",
+	"coordination":          "k8s.io",
+	"core":                  "",
+	"events":                "k8s.io",
+	"extensions":            "",
+	"imagepolicy":           "k8s.io",
+	"networking":            "k8s.io",
+	"node":                  "k8s.io",
+	"metrics":               "k8s.io",
+	"policy":                "",
+	"rbac.authorization":    "k8s.io",
+	"scheduling":            "k8s.io",
+	"setting":               "k8s.io",
+	"storage":               "k8s.io",
+}
+
+// IsCoreGroup returns true if group is a Kubernetes API group, whose types
+// are in k8s.io/api.
+func IsCoreGroup(group string) bool {
+	_, found := coreGroups[group]
+	return found
+}
+
+// GetResourceInfo returns the package holding the version packages of the
+// API of r, and its group with the domain. Core resources are used from
+// k8s.io/api unless the project has their types.
 func GetResourceInfo(r *resource.Resource, in input.Input) (resourcePackage, groupDomain string) {
-	// Use the k8s.io/api package for core resources
-	coreGroups := map[string]string{
-		"apps":                  "",
-		"admission":             "k8s.io",
-		"admissionregistration": "k8s.io",
-		"auditregistration":     "k8s.io",
-		"apiextensions":         "k8s.io",
-		"authentication":        "k8s.io",
-		"authorization":         "k8s.io",
-		"autoscaling":           "",
-		"batch":                 "",
-		"certificates":          "k8s.io",
-		"coordination":          "k8s.io",
-		"core":                  "",
-		"events":                "k8s.io",
-		"extensions":            "",
-		"imagepolicy":           "k8s.io",
-		"networking":            "k8s.io",
-		"node":                  "k8s.io",
-		"metrics":               "k8s.io",
-		"policy":                "",
-		"rbac.authorization":    "k8s.io",
-		"scheduling":            "k8s.io",
-		"setting":               "k8s.io",
-		"storage":               "k8s.io",
-	}
-	resourcePath := filepath.Join("api", r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
-	if _, err := os.Stat(resourcePath); os.IsNotExist(err) {
+	resourcePath := TypesPath(r, in.MultiGroup)
+	if _, err := filesystem.Fs.Stat(resourcePath); os.IsNotExist(err) {
 		if domain, found := coreGroups[r.Group]; found {
 			// TODO: support apiextensions.k8s.io and metrics.k8s.io.
 			// apiextensions.k8s.io is in k8s.io/apiextensions-apiserver/pkg/apis/apiextensions
@@ -69,5 +82,28 @@ func GetResourceInfo(r *resource.Resource, in input.Input) (resourcePackage, gro
 		}
 		// TODO: need to support '--resource-pkg-path' flag for specifying resourcePath
 	}
-	return path.Join(in.Repo, "api"), r.Group + "." + in.Domain
+	return path.Join(in.Repo, filepath.ToSlash(filepath.Dir(APIDir(r, in.MultiGroup)))), r.Group + "." + in.Domain
+}
+
+// APIDir returns the directory of the Go package of the API version of r,
+// api/<version>, or api/<group>/<version> in multi group projects.
+func APIDir(r *resource.Resource, multiGroup bool) string {
+	if multiGroup {
+		return filepath.Join("api", r.Group, r.Version)
+	}
+	return filepath.Join("api", r.Version)
+}
+
+// TypesPath returns the path of the Go file with the API types of r.
+func TypesPath(r *resource.Resource, multiGroup bool) string {
+	return filepath.Join(APIDir(r, multiGroup), fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
+}
+
+// ControllersDir returns the directory of the Go package of the controllers
+// of group, controllers, or controllers/<group> in multi group projects.
+func ControllersDir(group string, multiGroup bool) string {
+	if multiGroup {
+		return filepath.Join("controllers", group)
+	}
+	return "controllers"
 }
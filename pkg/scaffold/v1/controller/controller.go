@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/gobuffalo/flect"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
 )
@@ -83,7 +84,7 @@ func (a *Controller) GetInput() (input.Input, error) {
 func getResourceInfo(coreGroups map[string]string, r *resource.Resource, in input.Input) (resourcePackage, groupDomain string) {
 	resourcePath := filepath.Join("pkg", "apis", r.Group, r.Version,
 		fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
-	if _, err := os.Stat(resourcePath); os.IsNotExist(err) {
+	if _, err := filesystem.Fs.Stat(resourcePath); os.IsNotExist(err) {
 		if domain, found := coreGroups[r.Group]; found {
 			resourcePackage := path.Join("k8s.io", "api")
 			groupDomain = r.Group
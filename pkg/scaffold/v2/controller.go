@@ -54,7 +54,7 @@ func (a *Controller) GetInput() (input.Input, error) {
 	}
 
 	if a.Path == "" {
-		a.Path = filepath.Join("controllers",
+		a.Path = filepath.Join(util.ControllersDir(a.Resource.Group, a.MultiGroup),
 			strings.ToLower(a.Resource.Kind)+"_controller.go")
 	}
 	a.TemplateBody = controllerTemplate
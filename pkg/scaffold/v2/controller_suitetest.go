@@ -50,7 +50,7 @@ type ControllerSuiteTest struct {
 // GetInput implements input.File
 func (v *ControllerSuiteTest) GetInput() (input.Input, error) {
 	if v.Path == "" {
-		v.Path = filepath.Join("controllers", "suite_test.go")
+		v.Path = filepath.Join(util.ControllersDir(v.Resource.Group, v.MultiGroup), "suite_test.go")
 	}
 	v.TemplateBody = controllerSuiteTestTemplate
 	return v.Input, nil
@@ -102,7 +102,7 @@ var _ = BeforeSuite(func(done Done) {
 
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{filepath.Join({{ if .MultiGroup }}"..", {{ end }}"..", "config", "crd", "bases")},
 	}
 
 	var err error
@@ -130,30 +130,57 @@ var _ = AfterSuite(func() {
 // Update updates given file (suite_test.go) with code fragments required for
 // adding import paths and code setup for new types.
 func (a *ControllerSuiteTest) Update() error {
+	apiImportName, apiImportPath, addschemeCodeFragment := a.schemeCode()
 
-	a.ResourcePackage, a.GroupDomain = util.GetResourceInfo(a.Resource, a.Input)
-	if a.Plural == "" {
-		a.Plural = flect.Pluralize(strings.ToLower(a.Resource.Kind))
+	f, err := internal.LoadGoFile(a.Path)
+	if err != nil {
+		return err
+	}
+	if err := f.AddImport(apiImportName, apiImportPath); err != nil {
+		return err
+	}
+	// the scheme is set up in the BeforeSuite function literal
+	if err := f.InsertStatements("", apiSchemeScaffoldMarker, addschemeCodeFragment); err != nil {
+		return err
+	}
+	if err := f.Save(); err != nil {
+		return err
 	}
 
-	ctrlImportCodeFragment := fmt.Sprintf(`"%s/controllers"
-`, a.Repo)
-	apiImportCodeFragment := fmt.Sprintf(`%s%s "%s/%s"
-`, a.Resource.Group, a.Resource.Version, a.ResourcePackage, a.Resource.Version)
-
-	addschemeCodeFragment := fmt.Sprintf(`err = %s%s.AddToScheme(scheme.Scheme)
-Expect(err).NotTo(HaveOccurred())
+	return nil
+}
 
-`, a.Resource.Group, a.Resource.Version)
+// Remove removes the code fragments added by Update from suite_test.go, for
+// removing the last resource of a group version.
+func (a *ControllerSuiteTest) Remove() error {
+	apiImportName, apiImportPath, addschemeCodeFragment := a.schemeCode()
 
-	err := internal.InsertStringsInFile(a.Path,
-		map[string][]string{
-			apiPkgImportScaffoldMarker: []string{ctrlImportCodeFragment, apiImportCodeFragment},
-			apiSchemeScaffoldMarker:    []string{addschemeCodeFragment},
-		})
+	f, err := internal.LoadGoFile(a.Path)
 	if err != nil {
 		return err
 	}
+	if _, err := f.RemoveStatements("", addschemeCodeFragment); err != nil {
+		return err
+	}
+	if err := f.RemoveImport(apiImportName, apiImportPath); err != nil {
+		return err
+	}
+	return f.Save()
+}
 
-	return nil
+// schemeCode returns the import and the code fragment adding the group
+// version of the resource to the scheme of the tests.
+func (a *ControllerSuiteTest) schemeCode() (importName, importPath, code string) {
+	a.ResourcePackage, a.GroupDomain = util.GetResourceInfo(a.Resource, a.Input)
+	if a.Plural == "" {
+		a.Plural = flect.Pluralize(strings.ToLower(a.Resource.Kind))
+	}
+
+	importName = a.Resource.Group + a.Resource.Version
+	importPath = fmt.Sprintf("%s/%s", a.ResourcePackage, a.Resource.Version)
+	code = fmt.Sprintf(`err = %s%s.AddToScheme(scheme.Scheme)
+Expect(err).NotTo(HaveOccurred())
+
+`, a.Resource.Group, a.Resource.Version)
+	return importName, importPath, code
 }
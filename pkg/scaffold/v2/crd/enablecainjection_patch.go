@@ -19,9 +19,6 @@ package crd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
@@ -38,9 +35,8 @@ type EnableCAInjectionPatch struct {
 // GetInput implements input.File
 func (p *EnableCAInjectionPatch) GetInput() (input.Input, error) {
 	if p.Path == "" {
-		plural := flect.Pluralize(strings.ToLower(p.Resource.Kind))
 		p.Path = filepath.Join("config", "crd", "patches",
-			fmt.Sprintf("cainjection_in_%s.yaml", plural))
+			fmt.Sprintf("cainjection_in_%s.yaml", Plural(p.Resource, p.MultiGroup)))
 	}
 	p.TemplateBody = EnableCAInjectionPatchTemplate
 	return p.Input, nil
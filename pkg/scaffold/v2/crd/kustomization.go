@@ -19,9 +19,6 @@ package crd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
@@ -54,25 +51,87 @@ func (c *Kustomization) GetInput() (input.Input, error) {
 	return c.Input, nil
 }
 
+// Update adds the CRD of the resource and its patches to the kustomization
+// file.
 func (c *Kustomization) Update() error {
-	if c.Path == "" {
-		c.Path = filepath.Join("config", "crd", "kustomization.yaml")
+	k, err := c.load()
+	if err != nil {
+		return err
 	}
+	for _, item := range c.items() {
+		if err := k.Add(item); err != nil {
+			return err
+		}
+	}
+	return k.Save()
+}
 
-	// TODO(directxman12): not technically valid if something changes from the default
-	// (we'd need to parse the markers)
-	plural := flect.Pluralize(strings.ToLower(c.Resource.Kind))
+// Remove removes the entries of the resource added by Update, commented out
+// or not.
+func (c *Kustomization) Remove() error {
+	k, err := c.load()
+	if err != nil {
+		return err
+	}
+	for _, item := range c.items() {
+		if _, err := k.Remove(item.List, item.Item); err != nil {
+			return err
+		}
+	}
+	return k.Save()
+}
 
-	kustomizeResourceCodeFragment := fmt.Sprintf("- bases/%s.%s_%s.yaml\n", c.Resource.Group, c.Domain, plural)
-	kustomizeWebhookPatchCodeFragment := fmt.Sprintf("#- patches/webhook_in_%s.yaml\n", plural)
-	kustomizeCAInjectionPatchCodeFragment := fmt.Sprintf("#- patches/cainjection_in_%s.yaml\n", plural)
+// Rename replaces the entries of the resource added by Update by the
+// entries of the resource of to, keeping them where they are.
+func (c *Kustomization) Rename(to *Kustomization) error {
+	k, err := c.load()
+	if err != nil {
+		return err
+	}
+	newItems := to.items()
+	for i, item := range c.items() {
+		if _, err := k.Rename(item.List, item.Item, newItems[i].Item); err != nil {
+			return err
+		}
+	}
+	return k.Save()
+}
+
+// CRDPath returns the path of the CRD of the resource generated by
+// controller-gen, as listed in the resources of the kustomization.
+func (c *Kustomization) CRDPath() string {
+	return filepath.Join("config", "crd", filepath.FromSlash(c.items()[0].Item))
+}
 
-	return internal.InsertStringsInFile(c.Path,
-		map[string][]string{
-			kustomizeResourceScaffoldMarker:         {kustomizeResourceCodeFragment},
-			kustomizeWebhookPatchScaffoldMarker:     {kustomizeWebhookPatchCodeFragment},
-			kustomizeCAInjectionPatchScaffoldMarker: {kustomizeCAInjectionPatchCodeFragment},
-		})
+func (c *Kustomization) load() (*internal.Kustomization, error) {
+	if c.Path == "" {
+		c.Path = filepath.Join("config", "crd", "kustomization.yaml")
+	}
+	return internal.LoadKustomization(c.Path)
+}
+
+// items returns the entries of the resource in the kustomization
+func (c *Kustomization) items() []internal.ListItem {
+	plural := Plural(c.Resource, c.MultiGroup)
+	return []internal.ListItem{
+		{
+			List:   "resources",
+			Item:   fmt.Sprintf("bases/%s.%s_%s.yaml", c.Resource.Group, c.Domain, plural),
+			Marker: kustomizeResourceScaffoldMarker,
+		},
+		{
+			List:      "patches",
+			Item:      fmt.Sprintf("patches/webhook_in_%s.yaml", plural),
+			Commented: true,
+			Marker:    kustomizeWebhookPatchScaffoldMarker,
+		},
+		{
+			List:      "patches",
+			Item:      fmt.Sprintf("patches/cainjection_in_%s.yaml", plural),
+			Commented: true,
+			Marker:    kustomizeCAInjectionPatchScaffoldMarker,
+		},
+	}
 }
 
 var kustomizationTemplate = fmt.Sprintf(`# This kustomization.yaml is not intended to be run by itself,
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+)
+
+const resourceMarker = "+kubebuilder:resource:"
+
+// Plural returns the plural name of the CRD of r, as used for its file names.
+// It is the path of the +kubebuilder:resource marker in the types file of r
+// if it has one, otherwise the resource name of r.
+func Plural(r *resource.Resource, multiGroup bool) string {
+	typesPath := util.TypesPath(r, multiGroup)
+	if b, err := afero.ReadFile(filesystem.Fs, typesPath); err == nil {
+		if p := markerPath(b); p != "" {
+			return p
+		}
+	}
+	if r.Resource != "" {
+		return r.Resource
+	}
+	return flect.Pluralize(strings.ToLower(r.Kind))
+}
+
+// markerPath returns the path argument of the first +kubebuilder:resource
+// marker in src, e.g. frigates for
+// +kubebuilder:resource:path=frigates,scope=Cluster.
+func markerPath(src []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "//") {
+			continue
+		}
+		i := strings.Index(line, resourceMarker)
+		if i < 0 {
+			continue
+		}
+		for _, arg := range strings.Split(line[i+len(resourceMarker):], ",") {
+			if kv := strings.SplitN(strings.TrimSpace(arg), "=", 2); len(kv) == 2 && kv[0] == "path" {
+				return kv[1]
+			}
+		}
+	}
+	return ""
+}
@@ -33,6 +33,7 @@ func (c *Dockerfile) GetInput() (input.Input, error) {
 		c.Path = "Dockerfile"
 	}
 	c.TemplateBody = dockerfileTemplate
+	c.Input.Mergeable = true
 	return c.Input, nil
 }
 
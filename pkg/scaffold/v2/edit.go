@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+// AddImport adds the import of importPath with the given name, which may be
+// empty, to the Go file at path unless it is already imported.
+func AddImport(path, name, importPath string) error {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	if err := f.AddImport(name, importPath); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// InsertStatements inserts the statements in code before the marker comment
+// in the function fn of the Go file at path, see internal.GoFile.
+func InsertStatements(path, fn, marker, code string) error {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	if err := f.InsertStatements(fn, marker, code); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// IsInsertionPointError returns true if err is the error of an insertion
+// point which cannot be found, see InsertStatements.
+func IsInsertionPointError(err error) bool {
+	_, ok := err.(*internal.InsertionPointError)
+	return ok
+}
+
+// AddListItem adds item to the list of the kustomization file at path,
+// commented out or not, before the marker comment of the list if it has one,
+// see internal.ListItem. An equal item commented out is uncommented instead,
+// unless item is added commented out.
+func AddListItem(path, list, item string, commented bool, marker string) error {
+	k, err := internal.LoadKustomization(path)
+	if err != nil {
+		return err
+	}
+	if err := k.Add(internal.ListItem{List: list, Item: item, Commented: commented, Marker: marker}); err != nil {
+		return err
+	}
+	return k.Save()
+}
+
+// RemoveStatements removes the statements equivalent to code from the
+// function fn of the Go file at path, or from anywhere in the file if fn is
+// empty. It returns false if the statements cannot be found.
+func RemoveStatements(path, fn, code string) (bool, error) {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return false, err
+	}
+	removed, err := f.RemoveStatements(fn, code)
+	if err != nil || !removed {
+		return removed, err
+	}
+	return true, f.Save()
+}
+
+// RemoveImport removes the import of importPath with the given name, which
+// may be empty, from the Go file at path unless it is still used.
+func RemoveImport(path, name, importPath string) error {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	if err := f.RemoveImport(name, importPath); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// RemoveMethods removes the methods of the given types from the Go file at
+// path.
+func RemoveMethods(path string, types ...string) error {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	if err := f.RemoveMethods(types...); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// RemoveListItem removes the items equal to item, commented out or not, from
+// the list of the kustomization file at path.
+func RemoveListItem(path, list, item string) error {
+	k, err := internal.LoadKustomization(path)
+	if err != nil {
+		return err
+	}
+	if _, err := k.Remove(list, item); err != nil {
+		return err
+	}
+	return k.Save()
+}
@@ -25,6 +25,12 @@ var _ input.File = &GoMod{}
 // GoMod writes a templatefile for go.mod
 type GoMod struct {
 	input.Input
+
+	// Require are additional requirements, e.g. "github.com/pkg/errors v0.8.1"
+	Require []string
+
+	// Replace are replace directives, e.g. "github.com/pkg/errors => ../errors"
+	Replace []string
 }
 
 // GetInput implements input.File
@@ -44,5 +50,16 @@ go 1.12
 
 require (
 	sigs.k8s.io/controller-runtime v0.2.0-beta.4
+{{- range .Require }}
+	{{ . }}
+{{- end }}
+)
+{{- if .Replace }}
+
+replace (
+{{- range .Replace }}
+	{{ . }}
+{{- end }}
 )
+{{- end }}
 `
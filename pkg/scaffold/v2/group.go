@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
 )
 
@@ -36,7 +37,7 @@ type Group struct {
 // GetInput implements input.File
 func (g *Group) GetInput() (input.Input, error) {
 	if g.Path == "" {
-		g.Path = filepath.Join("api", g.Resource.Version, "groupversion_info.go")
+		g.Path = filepath.Join(util.APIDir(g.Resource, g.MultiGroup), "groupversion_info.go")
 	}
 	g.TemplateBody = groupTemplate
 	return g.Input, nil
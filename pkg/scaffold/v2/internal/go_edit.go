@@ -0,0 +1,394 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+)
+
+// GoFile edits the syntax tree of a Go source file, so code is only added
+// where it belongs and only if it is not there yet.
+type GoFile struct {
+	path   string
+	before []byte
+	src    []byte
+}
+
+// LoadGoFile reads the Go source file at path for editing.
+func LoadGoFile(path string) (*GoFile, error) {
+	b, err := afero.ReadFile(filesystem.Fs, path)
+	if err != nil {
+		return nil, err
+	}
+	return &GoFile{path: path, before: b, src: b}, nil
+}
+
+func (f *GoFile) parse() (*token.FileSet, *ast.File, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.path, f.src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %v", f.path, err)
+	}
+	return fset, file, nil
+}
+
+// AddImport adds the import of path with the given name, which may be empty,
+// unless it is already imported.
+func (f *GoFile) AddImport(name, path string) error {
+	fset, file, err := f.parse()
+	if err != nil {
+		return err
+	}
+	if !astutil.AddNamedImport(fset, file, name, path) {
+		return nil
+	}
+	out := &bytes.Buffer{}
+	if err := format.Node(out, fset, file); err != nil {
+		return fmt.Errorf("failed to format %s: %v", f.path, err)
+	}
+	f.src = out.Bytes()
+	return nil
+}
+
+// InsertStatements inserts the statements in code on the line before the
+// marker comment, which must be in the body of the function fn, or anywhere
+// in the file if fn is empty. The code is not inserted if the block holding
+// the marker already has a statement equivalent to the first statement of
+// the code, see equivalenceKey.
+func (f *GoFile) InsertStatements(fn, marker, code string) error {
+	fset, file, comment, err := f.insertionPoint(fn, marker)
+	if err != nil {
+		return err
+	}
+
+	stmts, err := parseStatements(code)
+	if err != nil {
+		return fmt.Errorf("invalid code to insert in %s: %v", f.path, err)
+	}
+	if len(stmts) == 0 {
+		return nil
+	}
+
+	block := enclosingBlock(file, comment.Pos())
+	if block == nil {
+		return fmt.Errorf("the insertion point %q in %s is not in a function body", marker, f.path)
+	}
+	key := equivalenceKey(stmts[0])
+	for _, s := range block.List {
+		if equivalenceKey(s) == key {
+			return nil
+		}
+	}
+
+	// insert the code as text at the start of the marker line, so the
+	// comments of the file are kept where they are
+	offset := fset.Position(comment.Pos()).Offset
+	offset = bytes.LastIndexByte(f.src[:offset], '\n') + 1
+	src := make([]byte, 0, len(f.src)+len(code)+1)
+	src = append(src, f.src[:offset]...)
+	src = append(src, strings.TrimRight(code, "\n")+"\n"...)
+	src = append(src, f.src[offset:]...)
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("failed to insert code in %s: %v", f.path, err)
+	}
+	f.src = formatted
+	return nil
+}
+
+// RemoveStatements removes the statements in the body of the function fn, or
+// anywhere in the file if fn is empty, which are equivalent to the statements
+// in code, see equivalenceKey. It returns false if they cannot be found.
+func (f *GoFile) RemoveStatements(fn, code string) (bool, error) {
+	stmts, err := parseStatements(code)
+	if err != nil {
+		return false, fmt.Errorf("invalid code to remove from %s: %v", f.path, err)
+	}
+	if len(stmts) == 0 {
+		return false, nil
+	}
+	keys := make([]string, len(stmts))
+	for i, s := range stmts {
+		keys[i] = equivalenceKey(s)
+	}
+
+	fset, file, err := f.parse()
+	if err != nil {
+		return false, err
+	}
+	var scope ast.Node = file
+	if fn != "" {
+		decl := findFunc(file, fn)
+		if decl == nil || decl.Body == nil {
+			return false, fmt.Errorf("cannot find function %s in %s", fn, f.path)
+		}
+		scope = decl.Body
+	}
+
+	var ranges [][2]token.Pos
+	ast.Inspect(scope, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i := 0; i+len(keys) <= len(block.List); i++ {
+			match := true
+			for j, key := range keys {
+				if equivalenceKey(block.List[i+j]) != key {
+					match = false
+					break
+				}
+			}
+			if match {
+				ranges = append(ranges, [2]token.Pos{block.List[i].Pos(), block.List[i+len(keys)-1].End()})
+				i += len(keys) - 1
+			}
+		}
+		return true
+	})
+	if len(ranges) == 0 {
+		return false, nil
+	}
+	return true, f.cut(fset, ranges)
+}
+
+// RemoveMethods removes the methods of the given types with their doc
+// comments.
+func (f *GoFile) RemoveMethods(types ...string) error {
+	fset, file, err := f.parse()
+	if err != nil {
+		return err
+	}
+	remove := map[string]bool{}
+	for _, t := range types {
+		remove[t] = true
+	}
+
+	var ranges [][2]token.Pos
+	for _, d := range file.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 {
+			continue
+		}
+		recv := fd.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if id, ok := recv.(*ast.Ident); ok && remove[id.Name] {
+			start := fd.Pos()
+			if fd.Doc != nil {
+				start = fd.Doc.Pos()
+			}
+			ranges = append(ranges, [2]token.Pos{start, fd.End()})
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	return f.cut(fset, ranges)
+}
+
+// RemoveImport removes the import of path with the given name, which may be
+// empty, unless it is still used.
+func (f *GoFile) RemoveImport(name, path string) error {
+	fset, file, err := f.parse()
+	if err != nil {
+		return err
+	}
+	if astutil.UsesImport(file, path) {
+		return nil
+	}
+	if !astutil.DeleteNamedImport(fset, file, name, path) {
+		return nil
+	}
+	out := &bytes.Buffer{}
+	if err := format.Node(out, fset, file); err != nil {
+		return fmt.Errorf("failed to format %s: %v", f.path, err)
+	}
+	f.src = out.Bytes()
+	return nil
+}
+
+// cut removes the lines holding the ranges, which must be sorted and must
+// not overlap, as text so the comments of the file are kept where they are.
+func (f *GoFile) cut(fset *token.FileSet, ranges [][2]token.Pos) error {
+	src := f.src
+	for i := len(ranges) - 1; i >= 0; i-- {
+		start := fset.Position(ranges[i][0]).Offset
+		end := fset.Position(ranges[i][1]).Offset
+		start = bytes.LastIndexByte(src[:start], '\n') + 1
+		if nl := bytes.IndexByte(src[end:], '\n'); nl >= 0 {
+			end += nl + 1
+		} else {
+			end = len(src)
+		}
+		src = append(src[:start:start], src[end:]...)
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("failed to remove code from %s: %v", f.path, err)
+	}
+	f.src = formatted
+	return nil
+}
+
+// CheckInsertionPoint returns an error if the marker comment cannot be found
+// in the body of the function fn, or anywhere in the file if fn is empty.
+func (f *GoFile) CheckInsertionPoint(fn, marker string) error {
+	_, _, _, err := f.insertionPoint(fn, marker)
+	return err
+}
+
+func (f *GoFile) insertionPoint(fn, marker string) (*token.FileSet, *ast.File, *ast.Comment, error) {
+	fset, file, err := f.parse()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var scope ast.Node
+	if fn != "" {
+		decl := findFunc(file, fn)
+		if decl == nil || decl.Body == nil {
+			return nil, nil, nil, &InsertionPointError{Path: f.path, Func: fn, Marker: marker, NoFunc: true}
+		}
+		scope = decl.Body
+	}
+
+	comment := findComment(file, scope, marker)
+	if comment == nil {
+		return nil, nil, nil, &InsertionPointError{Path: f.path, Func: fn, Marker: marker}
+	}
+	return fset, file, comment, nil
+}
+
+// InsertionPointError is the error of an insertion point which cannot be
+// found in a Go file
+type InsertionPointError struct {
+	// Path is the path of the Go file
+	Path string
+
+	// Func is the function which must hold the marker, empty if it can be
+	// anywhere in the file
+	Func string
+
+	// Marker is the marker comment
+	Marker string
+
+	// NoFunc is set if the function is not found
+	NoFunc bool
+}
+
+func (e *InsertionPointError) Error() string {
+	if e.NoFunc {
+		return fmt.Sprintf("cannot find function %s in %s", e.Func, e.Path)
+	}
+	where := e.Path
+	if e.Func != "" {
+		where = fmt.Sprintf("function %s of %s", e.Func, e.Path)
+	}
+	return fmt.Sprintf("cannot find the insertion point %q in %s, add it where the code should be inserted",
+		e.Marker, where)
+}
+
+// Save writes the edited file.
+func (f *GoFile) Save() error {
+	if bytes.Equal(f.before, f.src) {
+		return nil
+	}
+	if err := afero.WriteFile(filesystem.Fs, f.path, f.src, os.ModePerm); err != nil {
+		return err
+	}
+	return manifest.Edited(f.path, f.before, f.src)
+}
+
+// findFunc returns the declaration of the function name in file.
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, d := range file.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == name {
+			return fd
+		}
+	}
+	return nil
+}
+
+// findComment returns the first comment in scope with the text marker, scope
+// is nil for the whole file.
+func findComment(file *ast.File, scope ast.Node, marker string) *ast.Comment {
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if scope != nil && (c.Pos() < scope.Pos() || c.End() > scope.End()) {
+				continue
+			}
+			if strings.TrimSpace(c.Text) == strings.TrimSpace(marker) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// enclosingBlock returns the innermost block holding pos.
+func enclosingBlock(file *ast.File, pos token.Pos) *ast.BlockStmt {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for _, n := range path {
+		if b, ok := n.(*ast.BlockStmt); ok {
+			return b
+		}
+	}
+	return nil
+}
+
+// parseStatements parses code as a list of statements.
+func parseStatements(code string) ([]ast.Stmt, error) {
+	src := "package p\nfunc _() {\n" + code + "\n}\n"
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	return file.Decls[0].(*ast.FuncDecl).Body.List, nil
+}
+
+// equivalenceKey returns the formatted statement without comments. For if
+// statements with an init statement, e.g. the set up of a reconciler, only
+// the init statement is used, so changes to the error handling do not make
+// the statement differ.
+func equivalenceKey(s ast.Stmt) string {
+	if is, ok := s.(*ast.IfStmt); ok && is.Init != nil {
+		s = is.Init
+	}
+	out := &bytes.Buffer{}
+	if err := printer.Fprint(out, token.NewFileSet(), s); err != nil {
+		return ""
+	}
+	return out.String()
+}
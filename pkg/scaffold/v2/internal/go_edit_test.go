@@ -0,0 +1,230 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+const goEditInput = `package main
+
+import (
+	"os"
+	// +kubebuilder:scaffold:imports
+)
+
+func init() {
+	// +kubebuilder:scaffold:scheme
+}
+
+func main() {
+	// +kubebuilder:scaffold:builder
+	os.Exit(0)
+}
+`
+
+const goEditExpected = `package main
+
+import (
+	shipv1 "example.com/api/v1"
+	"os"
+	// +kubebuilder:scaffold:imports
+)
+
+func init() {
+	_ = shipv1.AddToScheme(scheme)
+	// +kubebuilder:scaffold:scheme
+}
+
+func main() {
+	if err := (&shipv1.Frigate{}).SetupWebhookWithManager(mgr); err != nil {
+		os.Exit(1)
+	}
+	// +kubebuilder:scaffold:builder
+	os.Exit(0)
+}
+`
+
+func editMain(t *testing.T, setupErrorHandling string) {
+	f, err := LoadGoFile("main.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := f.AddImport("shipv1", "example.com/api/v1"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := f.InsertStatements("init", "// +kubebuilder:scaffold:scheme", "_ = shipv1.AddToScheme(scheme)\n"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	code := "if err := (&shipv1.Frigate{}).SetupWebhookWithManager(mgr); err != nil {\n" + setupErrorHandling + "\n}\n"
+	if err := f.InsertStatements("main", "// +kubebuilder:scaffold:builder", code); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestGoFileEdit(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	if err := afero.WriteFile(filesystem.Fs, "main.go", []byte(goEditInput), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	editMain(t, "os.Exit(1)")
+	// editing again, also with different error handling, adds nothing
+	editMain(t, "os.Exit(1)")
+	editMain(t, "panic(err)")
+
+	b, err := afero.ReadFile(filesystem.Fs, "main.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if string(b) != goEditExpected {
+		t.Errorf("got: %s and wanted: %s", b, goEditExpected)
+	}
+}
+
+func TestGoFileMissingInsertionPoint(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	src := strings.Replace(goEditInput, "\t// +kubebuilder:scaffold:builder\n", "", 1)
+	if err := afero.WriteFile(filesystem.Fs, "main.go", []byte(src), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	f, err := LoadGoFile("main.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	err = f.InsertStatements("main", "// +kubebuilder:scaffold:builder", "os.Exit(1)\n")
+	if err == nil || !strings.Contains(err.Error(), `cannot find the insertion point "// +kubebuilder:scaffold:builder" in function main of main.go`) {
+		t.Errorf("got error %v", err)
+	}
+
+	// the marker of another function is not used
+	err = f.InsertStatements("init", "// +kubebuilder:scaffold:builder", "os.Exit(1)\n")
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+
+	err = f.InsertStatements("setup", "// +kubebuilder:scaffold:scheme", "os.Exit(1)\n")
+	if err == nil || err.Error() != "cannot find function setup in main.go" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestGoFileRemove(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	if err := afero.WriteFile(filesystem.Fs, "main.go", []byte(goEditInput), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	editMain(t, "os.Exit(1)")
+
+	f, err := LoadGoFile("main.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	// the import is still used
+	if err := f.RemoveImport("shipv1", "example.com/api/v1"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	removed, err := f.RemoveStatements("main", "if err := (&shipv1.Frigate{}).SetupWebhookWithManager(mgr); err != nil {\nos.Exit(1)\n}\n")
+	if err != nil || !removed {
+		t.Fatalf("expected the webhook setup to be removed, got %v", err)
+	}
+	// without a function the statements are found anywhere
+	removed, err = f.RemoveStatements("", "_ = shipv1.AddToScheme(scheme)\n")
+	if err != nil || !removed {
+		t.Fatalf("expected the scheme to be removed, got %v", err)
+	}
+	removed, err = f.RemoveStatements("init", "_ = shipv1.AddToScheme(scheme)\n")
+	if err != nil || removed {
+		t.Errorf("expected nothing to be removed, got %v", err)
+	}
+	if err := f.RemoveImport("shipv1", "example.com/api/v1"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	b, err := afero.ReadFile(filesystem.Fs, "main.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if string(b) != goEditInput {
+		t.Errorf("got: %s and wanted: %s", b, goEditInput)
+	}
+}
+
+func TestGoFileRemoveMethods(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	src := `package v1
+
+// DeepCopy copies a Destroyer
+func (in *Destroyer) DeepCopy() *Destroyer {
+	return nil
+}
+
+// DeepCopy copies a Frigate
+func (in *Frigate) DeepCopy() *Frigate {
+	return nil
+}
+
+// DeepCopyObject copies a FrigateList
+func (in FrigateList) DeepCopyObject() runtime.Object {
+	return nil
+}
+`
+	if err := afero.WriteFile(filesystem.Fs, "zz_generated.deepcopy.go", []byte(src), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	f, err := LoadGoFile("zz_generated.deepcopy.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := f.RemoveMethods("Frigate", "FrigateList"); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	expected := `package v1
+
+// DeepCopy copies a Destroyer
+func (in *Destroyer) DeepCopy() *Destroyer {
+	return nil
+}
+`
+	if string(f.src) != expected {
+		t.Errorf("got: %s and wanted: %s", f.src, expected)
+	}
+}
@@ -0,0 +1,385 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v3"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+)
+
+// Kustomization edits the lists of a kustomization.yaml file. The file is
+// parsed as YAML to find the lists and their items, while new items are
+// added as text so the comments and the layout of the file are kept.
+type Kustomization struct {
+	path   string
+	before []byte
+	lines  []string
+}
+
+// ListItem is an item to add to a list of a kustomization file
+type ListItem struct {
+	// List is the top level field holding the list, e.g. resources or vars
+	List string
+
+	// Item is the YAML of the item, e.g. bases/ship.example.com_frigates.yaml
+	// or a mapping for a var
+	Item string
+
+	// Commented adds the item commented out, for users to enable it
+	Commented bool
+
+	// Marker is a comment of the list the item is inserted before, to group
+	// the items of a list. Without the marker the item is added after the
+	// last item of the list.
+	Marker string
+}
+
+// LoadKustomization reads the kustomization file at path for editing.
+func LoadKustomization(path string) (*Kustomization, error) {
+	b, err := afero.ReadFile(filesystem.Fs, path)
+	if err != nil {
+		return nil, err
+	}
+	k := &Kustomization{path: path, before: b}
+	if s := strings.TrimSuffix(string(b), "\n"); s != "" {
+		k.lines = strings.Split(s, "\n")
+	}
+	return k, nil
+}
+
+// Add adds item to its list, unless the list already has an equal item,
+// commented out or not. An equal item commented out is uncommented, unless
+// item is added commented out. The list is created if the file has none.
+func (k *Kustomization) Add(item ListItem) error {
+	var want interface{}
+	if err := yaml.Unmarshal([]byte(item.Item), &want); err != nil {
+		return fmt.Errorf("invalid item for %s in %s: %v", item.List, k.path, err)
+	}
+
+	if !item.Commented {
+		items, err := k.find(item.List, item.Item)
+		if err != nil {
+			return err
+		}
+		for _, it := range items {
+			if !it.commented {
+				return nil
+			}
+		}
+		if len(items) > 0 {
+			// uncomment the item where it is, keeping its layout
+			it := items[0]
+			for i := it.first; i <= it.last; i++ {
+				k.lines[i] = k.lines[i][:it.column] + k.lines[i][it.column+1:]
+			}
+			return nil
+		}
+	}
+
+	root, err := k.parse()
+	if err != nil {
+		return err
+	}
+
+	// find the list and the line of the field following it
+	var key, value *yaml.Node
+	end := len(k.lines)
+	if root != nil {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if key != nil {
+				end = root.Content[i].Line - 1
+				break
+			}
+			if root.Content[i].Value == item.List {
+				key, value = root.Content[i], root.Content[i+1]
+			}
+		}
+	}
+	if key == nil {
+		k.lines = append(k.lines, "", item.List+":")
+		k.lines = append(k.lines, formatItem(item, 0)...)
+		return nil
+	}
+
+	// lines of the list, 0-based and excluding the field itself
+	start := key.Line
+	switch {
+	case value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle != 0:
+		if len(value.Content) > 0 {
+			return fmt.Errorf("cannot add to the flow style list %s in %s", item.List, k.path)
+		}
+		k.lines[key.Line-1] = k.lines[key.Line-1][:key.Column-1] + item.List + ":"
+	case value.Kind == yaml.ScalarNode && value.Tag == "!!null":
+		if value.Value != "" {
+			// an explicit null, e.g. resources: ~
+			k.lines[key.Line-1] = k.lines[key.Line-1][:key.Column-1] + item.List + ":"
+		}
+	case value.Kind != yaml.SequenceNode:
+		return fmt.Errorf("%s in %s is not a list", item.List, k.path)
+	}
+
+	for _, n := range value.Content {
+		var got interface{}
+		if err := n.Decode(&got); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(got, want) {
+			return nil
+		}
+	}
+
+	indent := -1
+	if len(value.Content) > 0 {
+		// the column of the item is after the dash
+		indent = value.Content[0].Column - 3
+	}
+	insert := -1
+	last := -1
+	// the uncommented lines of the commented out item being read
+	var commented []string
+	duplicate := func() bool {
+		var got []interface{}
+		err := yaml.Unmarshal([]byte(strings.Join(commented, "\n")), &got)
+		return err == nil && len(got) == 1 && reflect.DeepEqual(got[0], want)
+	}
+	for i := start; i < end; i++ {
+		line := k.lines[i]
+		trimmed := strings.TrimSpace(line)
+		if item.Marker != "" && trimmed == strings.TrimSpace(item.Marker) {
+			insert = i
+			break
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			uncommented := strings.TrimPrefix(trimmed, "#")
+			switch {
+			case strings.HasPrefix(strings.TrimSpace(uncommented), "- "):
+				// a commented out item, e.g. #- patches/webhook_in_frigates.yaml
+				if duplicate() {
+					return nil
+				}
+				commented = []string{strings.TrimSpace(uncommented)}
+				if indent < 0 {
+					indent = strings.Index(line, "#")
+				}
+			case len(commented) > 0 && strings.HasPrefix(uncommented, "  "):
+				// a following line of a commented out item
+				commented = append(commented, uncommented)
+			default:
+				if duplicate() {
+					return nil
+				}
+				commented = nil
+				continue
+			}
+			if item.Commented {
+				last = i
+			}
+			continue
+		}
+
+		if duplicate() {
+			return nil
+		}
+		commented = nil
+		if trimmed != "" {
+			// a line of an item that is not commented out
+			last = i
+		}
+	}
+	if duplicate() {
+		return nil
+	}
+	if indent < 0 {
+		indent = 0
+	}
+	if insert < 0 {
+		insert = last + 1
+		if last < 0 {
+			insert = start
+		}
+	}
+
+	lines := append([]string{}, k.lines[:insert]...)
+	lines = append(lines, formatItem(item, indent)...)
+	k.lines = append(lines, k.lines[insert:]...)
+	return nil
+}
+
+// Remove removes the items of list equal to item, commented out or not. It
+// returns false if the list has no such item.
+func (k *Kustomization) Remove(list, item string) (bool, error) {
+	items, err := k.find(list, item)
+	if err != nil {
+		return false, err
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		k.lines = append(k.lines[:items[i].first], k.lines[items[i].last+1:]...)
+	}
+	return len(items) > 0, nil
+}
+
+// Rename replaces the items of list equal to item by newItem, keeping them
+// commented out or not. It returns false if the list has no such item.
+func (k *Kustomization) Rename(list, item, newItem string) (bool, error) {
+	items, err := k.find(list, item)
+	if err != nil {
+		return false, err
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		it := items[i]
+		lines := append([]string{}, k.lines[:it.first]...)
+		lines = append(lines, formatItem(ListItem{Item: newItem, Commented: it.commented}, it.column)...)
+		k.lines = append(lines, k.lines[it.last+1:]...)
+	}
+	return len(items) > 0, nil
+}
+
+// kustomizationItem is an item of a list as lines of the file
+type kustomizationItem struct {
+	first, last int
+	commented   bool
+	// column is the column of the dash, or of the comment of commented out
+	// items
+	column int
+	// text is the YAML of the item as list, uncommented
+	text []string
+}
+
+// find returns the items of list equal to item, commented out or not.
+func (k *Kustomization) find(list, item string) ([]*kustomizationItem, error) {
+	var want interface{}
+	if err := yaml.Unmarshal([]byte(item), &want); err != nil {
+		return nil, fmt.Errorf("invalid item for %s in %s: %v", list, k.path, err)
+	}
+
+	root, err := k.parse()
+	if err != nil || root == nil {
+		return nil, err
+	}
+	start, end := -1, len(k.lines)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if start >= 0 {
+			end = root.Content[i].Line - 1
+			break
+		}
+		if root.Content[i].Value == list {
+			start = root.Content[i].Line
+		}
+	}
+	if start < 0 {
+		return nil, nil
+	}
+
+	var items []*kustomizationItem
+	var cur *kustomizationItem
+	for i := start; i < end; i++ {
+		line := k.lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			uncommented := strings.TrimPrefix(trimmed, "#")
+			switch {
+			case strings.HasPrefix(strings.TrimSpace(uncommented), "- "):
+				cur = &kustomizationItem{first: i, last: i, commented: true,
+					column: strings.Index(line, "#"), text: []string{strings.TrimSpace(uncommented)}}
+				items = append(items, cur)
+			case cur != nil && cur.commented && strings.HasPrefix(uncommented, "  "):
+				cur.last = i
+				cur.text = append(cur.text, uncommented)
+			default:
+				cur = nil
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "- "):
+			cur = &kustomizationItem{first: i, last: i, column: strings.Index(line, "-"), text: []string{trimmed}}
+			items = append(items, cur)
+		case cur != nil && !cur.commented && trimmed != "" &&
+			len(line) > cur.column && strings.TrimSpace(line[:cur.column]) == "":
+			cur.last = i
+			cur.text = append(cur.text, line[cur.column:])
+		default:
+			cur = nil
+		}
+	}
+
+	var found []*kustomizationItem
+	for _, it := range items {
+		var got []interface{}
+		if err := yaml.Unmarshal([]byte(strings.Join(it.text, "\n")), &got); err == nil &&
+			len(got) == 1 && reflect.DeepEqual(got[0], want) {
+			found = append(found, it)
+		}
+	}
+	return found, nil
+}
+
+// Save writes the edited file.
+func (k *Kustomization) Save() error {
+	b := []byte(strings.Join(k.lines, "\n") + "\n")
+	if bytes.Equal(k.before, b) {
+		return nil
+	}
+	if err := afero.WriteFile(filesystem.Fs, k.path, b, os.ModePerm); err != nil {
+		return err
+	}
+	return manifest.Edited(k.path, k.before, b)
+}
+
+// parse returns the top level mapping of the file, or nil if it is empty.
+func (k *Kustomization) parse() (*yaml.Node, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(strings.Join(k.lines, "\n")), doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", k.path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a YAML mapping", k.path)
+	}
+	return root, nil
+}
+
+// formatItem returns the lines of item as list item with the dash at column
+// indent.
+func formatItem(item ListItem, indent int) []string {
+	prefix := strings.Repeat(" ", indent)
+	if item.Commented {
+		prefix += "#"
+	}
+	var lines []string
+	for i, l := range strings.Split(strings.TrimRight(item.Item, "\n"), "\n") {
+		if i == 0 {
+			lines = append(lines, prefix+"- "+l)
+		} else {
+			lines = append(lines, prefix+"  "+l)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+type kustomizationTest struct {
+	input    string
+	items    []ListItem
+	expected string
+}
+
+func TestKustomizationAdd(t *testing.T) {
+	tests := []kustomizationTest{
+		{ // items go before their marker, existing items are skipped
+			input: `resources:
+- bases/ship_frigates.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patches:
+# webhook patches
+#- patches/webhook_in_frigates.yaml
+# +kubebuilder:scaffold:crdkustomizewebhookpatch
+
+# ca injection patches
+# +kubebuilder:scaffold:crdkustomizecainjectionpatch
+
+configurations:
+- kustomizeconfig.yaml
+`,
+			items: []ListItem{
+				{List: "resources", Item: "bases/ship_frigates.yaml", Marker: "# +kubebuilder:scaffold:crdkustomizeresource"},
+				{List: "resources", Item: "bases/ship_destroyers.yaml", Marker: "# +kubebuilder:scaffold:crdkustomizeresource"},
+				{List: "patches", Item: "patches/webhook_in_frigates.yaml", Commented: true,
+					Marker: "# +kubebuilder:scaffold:crdkustomizewebhookpatch"},
+				{List: "patches", Item: "patches/webhook_in_destroyers.yaml", Commented: true,
+					Marker: "# +kubebuilder:scaffold:crdkustomizewebhookpatch"},
+				{List: "patches", Item: "patches/cainjection_in_destroyers.yaml", Commented: true,
+					Marker: "# +kubebuilder:scaffold:crdkustomizecainjectionpatch"},
+			},
+			expected: `resources:
+- bases/ship_frigates.yaml
+- bases/ship_destroyers.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patches:
+# webhook patches
+#- patches/webhook_in_frigates.yaml
+#- patches/webhook_in_destroyers.yaml
+# +kubebuilder:scaffold:crdkustomizewebhookpatch
+
+# ca injection patches
+#- patches/cainjection_in_destroyers.yaml
+# +kubebuilder:scaffold:crdkustomizecainjectionpatch
+
+configurations:
+- kustomizeconfig.yaml
+`,
+		},
+		{ // without markers items go after the last item, keeping the indentation
+			input: `namespace: system
+resources:
+  - manager.yaml
+  # the service
+  - service.yaml
+
+# trailing comment
+`,
+			items: []ListItem{
+				{List: "resources", Item: "webhook.yaml"},
+				{List: "resources", Item: "service.yaml"},
+			},
+			expected: `namespace: system
+resources:
+  - manager.yaml
+  # the service
+  - service.yaml
+  - webhook.yaml
+
+# trailing comment
+`,
+		},
+		{ // vars are mappings, commented out vars are not added twice
+			input: `bases:
+- ../crd
+vars:
+#- name: CERTIFICATE_NAMESPACE
+#  objref:
+#    kind: Certificate
+#    name: serving-cert
+`,
+			items: []ListItem{
+				{List: "vars", Item: "name: CERTIFICATE_NAMESPACE\nobjref:\n  kind: Certificate\n  name: serving-cert\n",
+					Commented: true},
+				{List: "vars", Item: "name: SERVICE_NAME\nobjref:\n  kind: Service\n  name: webhook-service\n",
+					Commented: true},
+				{List: "bases", Item: "../webhook", Commented: true},
+			},
+			expected: `bases:
+- ../crd
+#- ../webhook
+vars:
+#- name: CERTIFICATE_NAMESPACE
+#  objref:
+#    kind: Certificate
+#    name: serving-cert
+#- name: SERVICE_NAME
+#  objref:
+#    kind: Service
+#    name: webhook-service
+`,
+		},
+		{ // commented out items are uncommented
+			input: `bases:
+- ../crd
+#- ../webhook
+patches:
+  #- manager_webhook_patch.yaml
+vars:
+#- name: CERTIFICATE_NAME # the certificate
+#  objref:
+#    kind: Certificate
+#- name: SERVICE_NAME
+#  objref:
+#    kind: Service
+`,
+			items: []ListItem{
+				{List: "bases", Item: "../webhook"},
+				{List: "patches", Item: "manager_webhook_patch.yaml"},
+				{List: "vars", Item: "name: CERTIFICATE_NAME\nobjref:\n  kind: Certificate\n"},
+				{List: "vars", Item: "name: SERVICE_NAME\nobjref:\n  kind: Service\n", Commented: true},
+			},
+			expected: `bases:
+- ../crd
+- ../webhook
+patches:
+  - manager_webhook_patch.yaml
+vars:
+- name: CERTIFICATE_NAME # the certificate
+  objref:
+    kind: Certificate
+#- name: SERVICE_NAME
+#  objref:
+#    kind: Service
+`,
+		},
+		{ // missing and empty lists
+			input: `resources: []
+`,
+			items: []ListItem{
+				{List: "resources", Item: "role.yaml"},
+				{List: "patchesStrategicMerge", Item: "manager_patch.yaml"},
+			},
+			expected: `resources:
+- role.yaml
+
+patchesStrategicMerge:
+- manager_patch.yaml
+`,
+		},
+	}
+
+	oldFs := filesystem.Fs
+	defer func() { filesystem.Fs = oldFs }()
+
+	for _, test := range tests {
+		filesystem.Fs = afero.NewMemMapFs()
+		if err := afero.WriteFile(filesystem.Fs, "kustomization.yaml", []byte(test.input), 0600); err != nil {
+			t.Fatalf("error %v", err)
+		}
+		k, err := LoadKustomization("kustomization.yaml")
+		if err != nil {
+			t.Fatalf("error %v", err)
+		}
+		for _, item := range test.items {
+			if err := k.Add(item); err != nil {
+				t.Errorf("error %v", err)
+			}
+		}
+		if err := k.Save(); err != nil {
+			t.Fatalf("error %v", err)
+		}
+		b, err := afero.ReadFile(filesystem.Fs, "kustomization.yaml")
+		if err != nil {
+			t.Fatalf("error %v", err)
+		}
+		if string(b) != test.expected {
+			t.Errorf("got: %s and wanted: %s", b, test.expected)
+		}
+	}
+}
+
+func TestKustomizationAddNotAList(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	if err := afero.WriteFile(filesystem.Fs, "kustomization.yaml", []byte("namespace: system\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	k, err := LoadKustomization("kustomization.yaml")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	err = k.Add(ListItem{List: "namespace", Item: "other"})
+	if err == nil || err.Error() != "namespace in kustomization.yaml is not a list" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestKustomizationRemove(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	input := `resources:
+- bases/ship_frigates.yaml
+- bases/ship_destroyers.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patches:
+#- patches/webhook_in_frigates.yaml
+- patches/webhook_in_destroyers.yaml
+
+vars:
+#- name: FRIGATE
+#  objref:
+#    kind: Frigate
+- name: DESTROYER
+  objref:
+    kind: Destroyer
+`
+	expected := `resources:
+- bases/ship_destroyers.yaml
+# +kubebuilder:scaffold:crdkustomizeresource
+
+patches:
+
+vars:
+- name: DESTROYER
+  objref:
+    kind: Destroyer
+`
+	if err := afero.WriteFile(filesystem.Fs, "kustomization.yaml", []byte(input), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	k, err := LoadKustomization("kustomization.yaml")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	for _, item := range []ListItem{
+		{List: "resources", Item: "bases/ship_frigates.yaml"},
+		// commented out or not
+		{List: "patches", Item: "patches/webhook_in_frigates.yaml"},
+		{List: "patches", Item: "patches/webhook_in_destroyers.yaml"},
+		{List: "vars", Item: "name: FRIGATE\nobjref:\n  kind: Frigate"},
+	} {
+		removed, err := k.Remove(item.List, item.Item)
+		if err != nil || !removed {
+			t.Errorf("expected %s to be removed, got %v", item.Item, err)
+		}
+	}
+	if removed, err := k.Remove("resources", "bases/ship_cruisers.yaml"); err != nil || removed {
+		t.Errorf("expected nothing to be removed, got %v", err)
+	}
+	if err := k.Save(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	b, err := afero.ReadFile(filesystem.Fs, "kustomization.yaml")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if string(b) != expected {
+		t.Errorf("got: %s and wanted: %s", b, expected)
+	}
+}
+
+func TestKustomizationRename(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	input := `resources:
+- bases/ship_frigates.yaml
+- bases/ship_destroyers.yaml
+
+patches:
+#- patches/webhook_in_frigates.yaml
+`
+	expected := `resources:
+- bases/ship_corvettes.yaml
+- bases/ship_destroyers.yaml
+
+patches:
+#- patches/webhook_in_corvettes.yaml
+`
+	if err := afero.WriteFile(filesystem.Fs, "kustomization.yaml", []byte(input), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	k, err := LoadKustomization("kustomization.yaml")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	for _, item := range []ListItem{
+		{List: "resources", Item: "bases/ship_frigates.yaml"},
+		{List: "patches", Item: "patches/webhook_in_frigates.yaml"},
+	} {
+		newItem := strings.Replace(item.Item, "frigates", "corvettes", 1)
+		renamed, err := k.Rename(item.List, item.Item, newItem)
+		if err != nil || !renamed {
+			t.Errorf("expected %s to be renamed, got %v", item.Item, err)
+		}
+	}
+	if renamed, err := k.Rename("resources", "bases/ship_cruisers.yaml", "bases/ship_sloops.yaml"); err != nil || renamed {
+		t.Errorf("expected nothing to be renamed, got %v", err)
+	}
+	if err := k.Save(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	b, err := afero.ReadFile(filesystem.Fs, "kustomization.yaml")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if string(b) != expected {
+		t.Errorf("got: %s and wanted: %s", b, expected)
+	}
+}
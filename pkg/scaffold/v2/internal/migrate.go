@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// Names returns the names declared at the top level of the file, sorted: its
+// functions, types, variables and constants, but not its methods.
+func (f *GoFile) Names() ([]string, error) {
+	_, file, err := f.parse()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, id := range s.Names {
+						if id.Name != "_" {
+							names = append(names, id.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// FuncToMethod turns the function fn into the method name of the type recv,
+// e.g. *FrigateReconciler. The receiver is unnamed, the function does not
+// use one. A doc comment starting with the name of the function is changed
+// too. It returns false if the file has no function fn.
+func (f *GoFile) FuncToMethod(fn, recv, name string) (bool, error) {
+	fset, file, err := f.parse()
+	if err != nil {
+		return false, err
+	}
+	var fd *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Recv == nil && d.Name.Name == fn {
+			fd = d
+			break
+		}
+	}
+	if fd == nil {
+		return false, nil
+	}
+
+	// the source is edited from its end, so the offsets of the edits before
+	// are kept
+	src := f.src
+	offset := fset.Position(fd.Name.Pos()).Offset
+	src = splice(src, offset, len(fn), fmt.Sprintf("(%s) %s", recv, name))
+	if fd.Doc != nil {
+		if c := fd.Doc.List[0]; strings.HasPrefix(c.Text, "// "+fn+" ") {
+			src = splice(src, fset.Position(c.Pos()).Offset+len("// "), len(fn), name)
+		}
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return false, fmt.Errorf("failed to format %s: %v", f.path, err)
+	}
+	f.src = formatted
+	return true, nil
+}
+
+// splice returns src with the n bytes at offset replaced by s.
+func splice(src []byte, offset, n int, s string) []byte {
+	out := make([]byte, 0, len(src)-n+len(s))
+	out = append(out, src[:offset]...)
+	out = append(out, s...)
+	return append(out, src[offset+n:]...)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func TestGoFileMigrate(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	src := `package frigate
+
+import shipv1 "example.com/project/pkg/apis/ship/v1"
+
+var log = "frigate-controller"
+
+// Add creates a new Frigate Controller and adds it to the Manager
+func Add(mgr interface{}) error {
+	return add(mgr, &ReconcileFrigate{})
+}
+
+func add(mgr interface{}, r interface{}) error { return nil }
+
+// ReconcileFrigate reconciles a Frigate object
+type ReconcileFrigate struct{}
+
+func (r *ReconcileFrigate) Reconcile() interface{} {
+	return &shipv1.Frigate{}
+}
+`
+	expected := `package controllers
+
+import (
+	shipv1 "example.com/project/api/ship/v1"
+)
+
+var log = "frigate-controller"
+
+// SetupWithManager creates a new Frigate Controller and adds it to the Manager
+func (*FrigateReconciler) SetupWithManager(mgr interface{}) error {
+	return addFrigateController(mgr, &FrigateReconciler{})
+}
+
+func addFrigateController(mgr interface{}, r interface{}) error { return nil }
+
+// FrigateReconciler reconciles a Frigate object
+type FrigateReconciler struct{}
+
+func (r *FrigateReconciler) Reconcile() interface{} {
+	return &shipv1.Frigate{}
+}
+`
+	if err := afero.WriteFile(filesystem.Fs, "frigate_controller.go", []byte(src), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	f, err := LoadGoFile("frigate_controller.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	names, err := f.Names()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if expected := []string{"Add", "ReconcileFrigate", "add", "log"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("got: %v and wanted: %v", names, expected)
+	}
+
+	if ok, err := f.FuncToMethod("Remove", "*FrigateReconciler", "Teardown"); err != nil || ok {
+		t.Errorf("expected no function to be changed, got %v", err)
+	}
+	if ok, err := f.FuncToMethod("Add", "*ReconcileFrigate", "SetupWithManager"); err != nil || !ok {
+		t.Fatalf("expected Add to be changed, got %v", err)
+	}
+	// the package keeps its import name but moves
+	_, err = f.Rename(GoRename{
+		Idents: map[string]string{"ReconcileFrigate": "FrigateReconciler", "add": "addFrigateController"},
+		Packages: []PackageRename{{
+			Path:    "example.com/project/pkg/apis/ship/v1",
+			NewPath: "example.com/project/api/ship/v1",
+			Name:    "shipv1",
+			NewName: "shipv1",
+			Idents:  map[string]string{"Frigate": "Frigate"},
+		}},
+		Words:   NewWords(map[string]string{"ReconcileFrigate": "FrigateReconciler"}),
+		Package: "controllers",
+	})
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if string(f.src) != expected {
+		t.Errorf("got: %s and wanted: %s", f.src, expected)
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
+)
+
+// Words replaces whole words in text. All words are replaced in a single
+// pass, so a replacement is never replaced again.
+type Words struct {
+	re    *regexp.Regexp
+	words map[string]string
+}
+
+// NewWords returns the replacer of the words by their replacement. A word
+// must start and end with a letter, a digit or an underscore.
+func NewWords(words map[string]string) *Words {
+	w := &Words{words: map[string]string{}}
+	var quoted []string
+	for old, replacement := range words {
+		if old == replacement || old == "" {
+			continue
+		}
+		w.words[old] = replacement
+		quoted = append(quoted, old)
+	}
+	if len(quoted) == 0 {
+		return w
+	}
+	// the longest words first, as the leftmost alternative matches
+	sort.Slice(quoted, func(i, j int) bool {
+		if len(quoted[i]) != len(quoted[j]) {
+			return len(quoted[i]) > len(quoted[j])
+		}
+		return quoted[i] < quoted[j]
+	})
+	for i := range quoted {
+		quoted[i] = regexp.QuoteMeta(quoted[i])
+	}
+	w.re = regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	return w
+}
+
+// Replace returns s with the words replaced.
+func (w *Words) Replace(s string) string {
+	if w == nil || w.re == nil {
+		return s
+	}
+	return w.re.ReplaceAllStringFunc(s, func(word string) string {
+		return w.words[word]
+	})
+}
+
+// PackageRename renames identifiers of an imported package, which may move
+// to a new import path.
+type PackageRename struct {
+	// Path is the import path of the package
+	Path string
+
+	// NewPath is the import path of the renamed identifiers, which is Path
+	// if they stay in the package
+	NewPath string
+
+	// Name is the import name of Path used by the scaffolding, which is
+	// changed to NewName in the files using it
+	Name string
+
+	// NewName is the import name of NewPath
+	NewName string
+
+	// Idents are the renamed identifiers of the package
+	Idents map[string]string
+}
+
+// GoRename are the renames of a Go file
+type GoRename struct {
+	// Idents are the renamed identifiers of the package of the file
+	Idents map[string]string
+
+	// Packages are the renames of the packages imported by the file
+	Packages []PackageRename
+
+	// Words are the words renamed in the comments and string literals of
+	// the file, only if an identifier is renamed or with Force
+	Words *Words
+	Force bool
+
+	// Package is the new name of the package of the file, empty to keep it
+	Package string
+}
+
+// Rename applies r to the file. It returns false if nothing was renamed.
+func (f *GoFile) Rename(r GoRename) (bool, error) {
+	fset, file, err := f.parse()
+	if err != nil {
+		return false, err
+	}
+
+	// the renamed packages by their name in the file
+	imported := map[string]*PackageRename{}
+	for i := range r.Packages {
+		for _, spec := range file.Imports {
+			if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == r.Packages[i].Path {
+				imported[importName(spec)] = &r.Packages[i]
+			}
+		}
+	}
+
+	changed := false
+	moved := map[*PackageRename]bool{}
+	// the identifiers of selectors are fields and methods, or identifiers of
+	// imported packages
+	selected := map[*ast.Ident]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		selected[sel.Sel] = true
+		x, ok := sel.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return true
+		}
+		p := imported[x.Name]
+		if p == nil {
+			return true
+		}
+		name, ok := p.Idents[sel.Sel.Name]
+		if !ok {
+			return true
+		}
+		if sel.Sel.Name != name {
+			sel.Sel.Name = name
+			changed = true
+		}
+		if p.NewPath != p.Path || (x.Name == p.Name && p.Name != p.NewName) {
+			x.Name = p.NewName
+			moved[p] = true
+			changed = true
+		}
+		return true
+	})
+	ast.Inspect(file, func(n ast.Node) bool {
+		if _, ok := n.(*ast.ImportSpec); ok {
+			return false
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok || selected[id] || id == file.Name {
+			return true
+		}
+		if name, ok := r.Idents[id.Name]; ok && name != id.Name {
+			id.Name = name
+			changed = true
+		}
+		return true
+	})
+
+	for i := range r.Packages {
+		p := &r.Packages[i]
+		if !moved[p] {
+			continue
+		}
+		astutil.AddNamedImport(fset, file, p.NewName, p.NewPath)
+		for name, imp := range imported {
+			// the uses of the import named as the new one are of the new one
+			if imp == p && ((name != p.NewName && !usesName(file, name)) || (name == p.NewName && p.Path != p.NewPath)) {
+				deleteImport(fset, file, name, p.Path)
+			}
+		}
+	}
+
+	if r.Package != "" && file.Name.Name != r.Package {
+		file.Name.Name = r.Package
+		changed = true
+	}
+
+	if changed {
+		out := &bytes.Buffer{}
+		if err := format.Node(out, fset, file); err != nil {
+			return false, fmt.Errorf("failed to format %s: %v", f.path, err)
+		}
+		f.src = out.Bytes()
+	}
+	if !changed && !r.Force {
+		return false, nil
+	}
+	replaced, err := f.replaceWords(r.Words)
+	if err != nil {
+		return false, err
+	}
+	return changed || replaced, nil
+}
+
+// replaceWords replaces the words in the comments and string literals of the
+// file, except imports. The text is replaced in the source rather than in the
+// syntax tree, whose positions would not match the new lengths.
+func (f *GoFile) replaceWords(words *Words) (bool, error) {
+	fset, file, err := f.parse()
+	if err != nil {
+		return false, err
+	}
+	type span struct{ start, end int }
+	var spans []span
+	add := func(n ast.Node) {
+		spans = append(spans, span{fset.Position(n.Pos()).Offset, fset.Position(n.End()).Offset})
+	}
+	for _, cg := range file.Comments {
+		add(cg)
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if _, ok := n.(*ast.ImportSpec); ok {
+			return false
+		}
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			add(lit)
+		}
+		return true
+	})
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	out := &bytes.Buffer{}
+	last := 0
+	for _, s := range spans {
+		out.Write(f.src[last:s.start])
+		out.WriteString(words.Replace(string(f.src[s.start:s.end])))
+		last = s.end
+	}
+	out.Write(f.src[last:])
+	if bytes.Equal(out.Bytes(), f.src) {
+		return false, nil
+	}
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return false, fmt.Errorf("failed to format %s: %v", f.path, err)
+	}
+	f.src = src
+	return true, nil
+}
+
+// importName returns the name of the package imported by spec in the file,
+// guessed from the import path if the import is not named.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	p, _ := strconv.Unquote(spec.Path.Value)
+	return path.Base(p)
+}
+
+// usesName returns true if file uses the package imported as name.
+func usesName(file *ast.File, name string) bool {
+	used := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && x.Name == name {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// deleteImport removes the import of importPath named name, which may be the
+// name guessed from the path.
+func deleteImport(fset *token.FileSet, file *ast.File, name, importPath string) {
+	if !astutil.DeleteNamedImport(fset, file, name, importPath) {
+		astutil.DeleteImport(fset, file, importPath)
+	}
+}
+
+// SaveAs writes the edited file to path, moving it if path is not the path
+// the file was loaded from.
+func (f *GoFile) SaveAs(path string) error {
+	if filepath.Clean(path) == filepath.Clean(f.path) {
+		return f.Save()
+	}
+	return move(f.path, path, f.before, f.src)
+}
+
+// RenameFile replaces the words in the file at path and moves it to newPath.
+func RenameFile(path, newPath string, words *Words) error {
+	b, err := afero.ReadFile(filesystem.Fs, path)
+	if err != nil {
+		return err
+	}
+	renamed := []byte(words.Replace(string(b)))
+	if filepath.Clean(path) == filepath.Clean(newPath) {
+		if bytes.Equal(b, renamed) {
+			return nil
+		}
+		if err := afero.WriteFile(filesystem.Fs, path, renamed, os.ModePerm); err != nil {
+			return err
+		}
+		return manifest.Edited(path, b, renamed)
+	}
+	return move(path, newPath, b, renamed)
+}
+
+// move writes after to the file at to and removes the file at from, whose
+// content is before.
+func move(from, to string, before, after []byte) error {
+	if _, err := filesystem.Fs.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+	info, err := filesystem.Fs.Stat(from)
+	if err != nil {
+		return err
+	}
+	if err := filesystem.Fs.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(filesystem.Fs, to, after, info.Mode()); err != nil {
+		return err
+	}
+	if err := filesystem.Fs.Remove(from); err != nil {
+		return err
+	}
+	return manifest.Moved(from, to, before, after)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+)
+
+func TestWordsReplace(t *testing.T) {
+	w := NewWords(map[string]string{
+		"Frigate":     "Corvette",
+		"FrigateList": "CorvetteList",
+		"frigates":    "corvettes",
+		"v1":          "v1",
+	})
+	input := "Frigate FrigateList frigates Frigates myFrigate v1 /frigates/status"
+	expected := "Corvette CorvetteList corvettes Frigates myFrigate v1 /corvettes/status"
+	if got := w.Replace(input); got != expected {
+		t.Errorf("got: %s and wanted: %s", got, expected)
+	}
+
+	var none *Words
+	if got := none.Replace(input); got != input {
+		t.Errorf("got: %s and wanted: %s", got, input)
+	}
+}
+
+func TestGoFileRename(t *testing.T) {
+	oldFs := filesystem.Fs
+	filesystem.Fs = afero.NewMemMapFs()
+	defer func() { filesystem.Fs = oldFs }()
+
+	src := `package controllers
+
+import (
+	shipv1 "example.com/project/api/v1"
+)
+
+// +kubebuilder:rbac:groups=ship.example.com,resources=frigates,verbs=get
+
+// FrigateReconciler reconciles a Frigate object
+type FrigateReconciler struct{}
+
+func (r *FrigateReconciler) Setup() interface{} {
+	var frigate shipv1.Frigate
+	_ = shipv1.Destroyer{}
+	return &frigate
+}
+`
+	expected := `package controllers
+
+import (
+	shipv1 "example.com/project/api/v1"
+	shipv2 "example.com/project/api/v2"
+)
+
+// +kubebuilder:rbac:groups=ship.example.com,resources=corvettes,verbs=get
+
+// CorvetteReconciler reconciles a Corvette object
+type CorvetteReconciler struct{}
+
+func (r *CorvetteReconciler) Setup() interface{} {
+	var frigate shipv2.Corvette
+	_ = shipv1.Destroyer{}
+	return &frigate
+}
+`
+	if err := afero.WriteFile(filesystem.Fs, "frigate_controller.go", []byte(src), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	f, err := LoadGoFile("frigate_controller.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	renamed, err := f.Rename(GoRename{
+		Idents: map[string]string{"FrigateReconciler": "CorvetteReconciler"},
+		Packages: []PackageRename{{
+			Path:    "example.com/project/api/v1",
+			NewPath: "example.com/project/api/v2",
+			Name:    "shipv1",
+			NewName: "shipv2",
+			Idents:  map[string]string{"Frigate": "Corvette"},
+		}},
+		Words: NewWords(map[string]string{
+			"Frigate":           "Corvette",
+			"FrigateReconciler": "CorvetteReconciler",
+			"frigates":          "corvettes",
+		}),
+	})
+	if err != nil || !renamed {
+		t.Fatalf("expected the file to be renamed, got %v", err)
+	}
+	if string(f.src) != expected {
+		t.Errorf("got: %s and wanted: %s", f.src, expected)
+	}
+
+	// the words alone are only replaced with Force
+	f, err = LoadGoFile("frigate_controller.go")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	words := NewWords(map[string]string{"frigates": "corvettes"})
+	if renamed, err := f.Rename(GoRename{Words: words}); err != nil || renamed {
+		t.Errorf("expected nothing to be renamed, got %v", err)
+	}
+	if renamed, err := f.Rename(GoRename{Words: words, Force: true}); err != nil || !renamed {
+		t.Errorf("expected the words to be renamed, got %v", err)
+	}
+}
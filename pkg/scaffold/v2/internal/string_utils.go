@@ -24,7 +24,11 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/spf13/afero"
 	"golang.org/x/tools/imports"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/manifest"
 )
 
 // insertStrings reads content from given reader and insert string below the
@@ -75,17 +79,12 @@ func InsertStringsInFile(path string, markerAndValues map[string][]string) error
 		isGoFile = true
 	}
 
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-
-	r, err := insertStrings(f, markerAndValues)
+	before, err := afero.ReadFile(filesystem.Fs, path)
 	if err != nil {
 		return err
 	}
 
-	err = f.Close()
+	r, err := insertStrings(bytes.NewReader(before), markerAndValues)
 	if err != nil {
 		return err
 	}
@@ -104,12 +103,12 @@ func InsertStringsInFile(path string, markerAndValues map[string][]string) error
 	}
 
 	// use Go import process to format the content
-	err = ioutil.WriteFile(path, formattedContent, os.ModePerm)
+	err = afero.WriteFile(filesystem.Fs, path, formattedContent, os.ModePerm)
 	if err != nil {
 		return err
 	}
 
-	return err
+	return manifest.Edited(path, before, formattedContent)
 }
 
 // filterExistingValues removes the single-line values that already exists in
@@ -17,9 +17,9 @@ limitations under the License.
 package v2
 
 import (
-	"os"
 	"path/filepath"
 
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
@@ -40,7 +40,7 @@ func (c *Kustomize) GetInput() (input.Input, error) {
 	}
 	if c.Prefix == "" {
 		// use directory name as prefix
-		dir, err := os.Getwd()
+		dir, err := filesystem.Getwd()
 		if err != nil {
 			return input.Input{}, err
 		}
@@ -19,6 +19,7 @@ package v2
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
@@ -32,6 +33,56 @@ const (
 	reconcilerSetupScaffoldMarker = "// +kubebuilder:scaffold:builder"
 )
 
+// InsertionPoint is a marker comment kubebuilder inserts code at
+type InsertionPoint struct {
+	// Path is the path of the Go file
+	Path string
+
+	// Func is the function holding the marker, empty if it can be anywhere
+	// in the file
+	Func string
+
+	// Marker is the marker comment
+	Marker string
+}
+
+// InsertionPoints returns the insertion points of the project p, which must
+// exist to add resources to it.
+func InsertionPoints(p *input.ProjectFile) []InsertionPoint {
+	points := []InsertionPoint{
+		{Path: "main.go", Func: "init", Marker: apiSchemeScaffoldMarker},
+		{Path: "main.go", Func: "main", Marker: reconcilerSetupScaffoldMarker},
+	}
+	dirs := []string{util.ControllersDir("", false)}
+	if p.MultiGroup {
+		dirs = nil
+		seen := map[string]bool{}
+		for _, r := range p.Resources {
+			// the groups of controller only resources have controllers too
+			dir := util.ControllersDir(r.Group, true)
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+		sort.Strings(dirs)
+	}
+	for _, dir := range dirs {
+		points = append(points,
+			InsertionPoint{Path: filepath.Join(dir, "suite_test.go"), Marker: apiSchemeScaffoldMarker})
+	}
+	return points
+}
+
+// Check returns an error if the insertion point cannot be found.
+func (p InsertionPoint) Check() error {
+	f, err := internal.LoadGoFile(p.Path)
+	if err != nil {
+		return err
+	}
+	return f.CheckInsertionPoint(p.Func, p.Marker)
+}
+
 var _ input.File = &Main{}
 
 // Main scaffolds a main.go to run Controllers
@@ -45,6 +96,7 @@ func (m *Main) GetInput() (input.Input, error) {
 		m.Path = filepath.Join("main.go")
 	}
 	m.TemplateBody = mainTemplate
+	m.Input.Mergeable = true
 	return m.Input, nil
 }
 
@@ -52,63 +104,129 @@ func (m *Main) GetInput() (input.Input, error) {
 // resource/controller.
 func (m *Main) Update(opts *MainUpdateOptions) error {
 	path := "main.go"
+	c := newMainCode(opts)
+
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	if opts.WireResource || opts.WireController || opts.WireWebhook {
+		if err := f.AddImport(c.apiImportName, c.apiImportPath); err != nil {
+			return err
+		}
+		if err := f.InsertStatements("init", apiSchemeScaffoldMarker, c.addScheme); err != nil {
+			return err
+		}
+	}
+	if opts.WireController {
+		if err := f.AddImport(c.ctrlImportName, c.ctrlImportPath); err != nil {
+			return err
+		}
+		if err := f.InsertStatements("main", reconcilerSetupScaffoldMarker, c.reconcilerSetup); err != nil {
+			return err
+		}
+	}
+	if opts.WireWebhook {
+		if err := f.InsertStatements("main", reconcilerSetupScaffoldMarker, c.webhookSetup); err != nil {
+			return err
+		}
+	}
+	return f.Save()
+}
+
+// Remove removes the code fragments added by Update from main.go. The
+// scheme of the group version is only removed with WireResource, which is
+// set when the last resource of the group version is removed. Imports are
+// removed once they are unused.
+func (m *Main) Remove(opts *MainUpdateOptions) error {
+	path := "main.go"
+	c := newMainCode(opts)
 
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	fragments := []struct {
+		fn, code string
+		remove   bool
+	}{
+		{"main", c.reconcilerSetup, opts.WireController},
+		{"main", c.webhookSetup, opts.WireWebhook},
+		{"init", c.addScheme, opts.WireResource},
+	}
+	for _, fragment := range fragments {
+		if !fragment.remove {
+			continue
+		}
+		if _, err := f.RemoveStatements(fragment.fn, fragment.code); err != nil {
+			return err
+		}
+	}
+	if err := f.RemoveImport(c.ctrlImportName, c.ctrlImportPath); err != nil {
+		return err
+	}
+	if err := f.RemoveImport(c.apiImportName, c.apiImportPath); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// mainCode are the code fragments wiring a resource in main.go
+type mainCode struct {
+	apiImportName, apiImportPath   string
+	ctrlImportName, ctrlImportPath string
+
+	addScheme       string
+	reconcilerSetup string
+	webhookSetup    string
+}
+
+// newMainCode returns the code fragments wiring the resource of opts.
+func newMainCode(opts *MainUpdateOptions) mainCode {
 	resPkg, _ := util.GetResourceInfo(opts.Resource, input.Input{
-		Domain: opts.Project.Domain,
-		Repo:   opts.Project.Repo,
+		Domain:     opts.Project.Domain,
+		Repo:       opts.Project.Repo,
+		MultiGroup: opts.Project.MultiGroup,
 	})
 
 	// generate all the code fragments
-	apiImportCodeFragment := fmt.Sprintf(`%s%s "%s/%s"
-`, opts.Resource.Group, opts.Resource.Version, resPkg, opts.Resource.Version)
-	ctrlImportCodeFragment := fmt.Sprintf(`"%s/controllers"
-`, opts.Project.Repo)
-	addschemeCodeFragment := fmt.Sprintf(`_ = %s%s.AddToScheme(scheme)
+	c := mainCode{
+		apiImportName: opts.Resource.Group + opts.Resource.Version,
+		apiImportPath: fmt.Sprintf("%s/%s", resPkg, opts.Resource.Version),
+		ctrlImportPath: fmt.Sprintf("%s/%s", opts.Project.Repo,
+			filepath.ToSlash(util.ControllersDir(opts.Resource.Group, opts.Project.MultiGroup))),
+	}
+	ctrlPkg := "controllers"
+	if opts.Project.MultiGroup {
+		// the controllers of every group are in a package named controllers
+		c.ctrlImportName = opts.Resource.Group + "controllers"
+		ctrlPkg = c.ctrlImportName
+	}
+
+	c.addScheme = fmt.Sprintf(`_ = %s%s.AddToScheme(scheme)
 `, opts.Resource.Group, opts.Resource.Version)
-	reconcilerSetupCodeFragment := fmt.Sprintf(`if err = (&controllers.%sReconciler{
+	c.reconcilerSetup = fmt.Sprintf(`if err = (&%s.%sReconciler{
 	 	Client: mgr.GetClient(),
         Log: ctrl.Log.WithName("controllers").WithName("%s"),
 	}).SetupWithManager(mgr); err != nil {
 	 	setupLog.Error(err, "unable to create controller", "controller", "%s")
 	 	os.Exit(1)
     }
-`, opts.Resource.Kind, opts.Resource.Kind, opts.Resource.Kind)
-	webhookSetupCodeFragment := fmt.Sprintf(`if err = (&%s%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
+`, ctrlPkg, opts.Resource.Kind, opts.Resource.Kind, opts.Resource.Kind)
+	if opts.Migrated {
+		// the reconcilers of the v1 layout are created by SetupWithManager
+		c.reconcilerSetup = fmt.Sprintf(`if err = (&%s.%sReconciler{}).SetupWithManager(mgr); err != nil {
+	 	setupLog.Error(err, "unable to create controller", "controller", "%s")
+	 	os.Exit(1)
+    }
+`, ctrlPkg, opts.Resource.Kind, opts.Resource.Kind)
+	}
+	c.webhookSetup = fmt.Sprintf(`if err = (&%s%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "%s")
 		os.Exit(1)
 	}
 `, opts.Resource.Group, opts.Resource.Version, opts.Resource.Kind, opts.Resource.Kind)
-
-	if opts.WireResource {
-		err := internal.InsertStringsInFile(path,
-			map[string][]string{
-				apiPkgImportScaffoldMarker: {apiImportCodeFragment},
-				apiSchemeScaffoldMarker:    {addschemeCodeFragment},
-			})
-		if err != nil {
-			return err
-		}
-	}
-
-	if opts.WireController {
-		return internal.InsertStringsInFile(path,
-			map[string][]string{
-				apiPkgImportScaffoldMarker:    {apiImportCodeFragment, ctrlImportCodeFragment},
-				apiSchemeScaffoldMarker:       {addschemeCodeFragment},
-				reconcilerSetupScaffoldMarker: {reconcilerSetupCodeFragment},
-			})
-	}
-
-	if opts.WireWebhook {
-		return internal.InsertStringsInFile(path,
-			map[string][]string{
-				apiPkgImportScaffoldMarker:    {apiImportCodeFragment, ctrlImportCodeFragment},
-				apiSchemeScaffoldMarker:       {addschemeCodeFragment},
-				reconcilerSetupScaffoldMarker: {webhookSetupCodeFragment},
-			})
-	}
-
-	return nil
+	return c
 }
 
 // MainUpdateOptions contains info required for wiring an API/Controller in
@@ -124,6 +242,10 @@ type MainUpdateOptions struct {
 	WireResource   bool
 	WireController bool
 	WireWebhook    bool
+
+	// Migrated is set for controllers migrated from the v1 layout, whose
+	// reconcilers are set up with the manager without fields
+	Migrated bool
 }
 
 var mainTemplate = fmt.Sprintf(`{{ .Boilerplate }}
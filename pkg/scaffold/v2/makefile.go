@@ -38,7 +38,11 @@ func (c *Makefile) GetInput() (input.Input, error) {
 		c.Image = "controller:latest"
 	}
 	c.TemplateBody = makefileTemplate
-	c.Input.IfExistsAction = input.Error
+	// the Makefile is only replaced when merged, e.g. by rescaffold
+	if c.Input.IfExistsAction != input.Merge {
+		c.Input.IfExistsAction = input.Error
+	}
+	c.Input.Mergeable = true
 	return c.Input, nil
 }
 
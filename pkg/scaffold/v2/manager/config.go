@@ -37,6 +37,7 @@ func (c *Config) GetInput() (input.Input, error) {
 		c.Path = filepath.Join("config", "manager", "manager.yaml")
 	}
 	c.TemplateBody = configTemplate
+	c.Input.Mergeable = true
 	return c.Input, nil
 }
 
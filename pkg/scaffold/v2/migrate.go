@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"go/ast"
+	"path"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+// Migration converts the Go code of a version 1 project to the v2 layout:
+// the packages of the types move from pkg/apis/<group>/<version> to api, and
+// the controllers of pkg/controller/<kind> move to the controllers packages
+// with their Add function as the SetupWithManager method of the reconciler.
+type Migration struct {
+	project *input.ProjectFile
+	apis    []internal.PackageRename
+}
+
+// NewMigration returns the migration of the Go code of the project p, whose
+// PROJECT file is already migrated.
+func NewMigration(p *input.ProjectFile) *Migration {
+	return &Migration{project: p}
+}
+
+// schemeIdents are the identifiers of the types packages which are named
+// differently in the v2 layout
+var schemeIdents = map[string]string{"SchemeGroupVersion": "GroupVersion"}
+
+// AddAPI adds the move of the types package of the group version of r to the
+// migration. The Go files of the package are at paths.
+func (m *Migration) AddAPI(r *resource.Resource, paths []string) error {
+	idents := map[string]string{}
+	for _, p := range paths {
+		names, err := DeclaredNames(p)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if ast.IsExported(name) {
+				idents[name] = name
+			}
+		}
+	}
+	for ident, renamed := range schemeIdents {
+		idents[ident] = renamed
+	}
+	m.apis = append(m.apis, internal.PackageRename{
+		Path:    path.Join(m.project.Repo, "pkg", "apis", r.Group, r.Version),
+		NewPath: path.Join(m.project.Repo, filepath.ToSlash(util.APIDir(r, m.project.MultiGroup))),
+		Name:    r.Group + r.Version,
+		NewName: r.Group + r.Version,
+		Idents:  idents,
+	})
+	return nil
+}
+
+// APIFile moves the Go file at path of a types package to newPath.
+func (m *Migration) APIFile(path, newPath string) error {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Rename(internal.GoRename{Idents: schemeIdents, Packages: m.apis}); err != nil {
+		return err
+	}
+	return f.SaveAs(newPath)
+}
+
+// ControllerIdents returns the renames of the identifiers of the controller
+// of the kind in the v1 layout, which would collide with the controllers of
+// the other kinds in the controllers package.
+func ControllerIdents(kind string) map[string]string {
+	return map[string]string{
+		"Reconcile" + kind: kind + "Reconciler",
+		"newReconciler":    "new" + kind + "Reconciler",
+		"add":              "add" + kind + "Controller",
+	}
+}
+
+// ControllerFile converts the Go file at path of the controller of r in the
+// v1 layout and moves it to newPath, in the controllers package. The
+// identifiers of the controller package are renamed with idents, see
+// ControllerIdents. The Add function of the file becomes the
+// SetupWithManager method of the reconciler, it returns false if the file
+// has no Add function.
+func (m *Migration) ControllerFile(r *resource.Resource, path, newPath string, idents map[string]string) (bool, error) {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return false, err
+	}
+	setup, err := f.FuncToMethod("Add", "*"+r.Kind+"Reconciler", "SetupWithManager")
+	if err != nil {
+		return false, err
+	}
+	words := map[string]string{"Reconcile" + r.Kind: r.Kind + "Reconciler"}
+	_, err = f.Rename(internal.GoRename{
+		Idents:   idents,
+		Packages: m.apis,
+		Words:    internal.NewWords(words),
+		Force:    true,
+		// the controllers of every group are in a package named controllers
+		Package: "controllers",
+	})
+	if err != nil {
+		return false, err
+	}
+	return setup, f.SaveAs(newPath)
+}
+
+// GoFile updates the uses of the moved types packages in the Go file at
+// path. It returns false if the file does not use them.
+func (m *Migration) GoFile(path string) (bool, error) {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return false, err
+	}
+	changed, err := f.Rename(internal.GoRename{Packages: m.apis})
+	if err != nil || !changed {
+		return false, err
+	}
+	return true, f.Save()
+}
+
+// DeclaredNames returns the names declared at the top level of the Go file
+// at path, except methods.
+func DeclaredNames(path string) ([]string, error) {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.Names()
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+// Rename renames a resource in the files of a project, following the names
+// the scaffolding derives from the resource: the Go types of the kind, its
+// reconciler and webhook logger, and the group, version, kind and plural in
+// markers, webhook paths and manifests.
+type Rename struct {
+	from, to    *resource.Resource
+	multiGroup  bool
+	words       *internal.Words
+	api         internal.PackageRename
+	controllers internal.PackageRename
+}
+
+// NewRename returns the rename of the resource from to to in the project p.
+// The Resource fields of from and to are the plurals of their CRDs.
+func NewRename(p *input.ProjectFile, from, to *resource.Resource) *Rename {
+	r := &Rename{from: from, to: to, multiGroup: p.MultiGroup}
+
+	fromLower, toLower := strings.ToLower(from.Kind), strings.ToLower(to.Kind)
+	fromGroup, toGroup := from.Group+"."+p.Domain, to.Group+"."+p.Domain
+	// the paths of the webhooks end with e.g. ship-example-com-v1-frigate
+	fromPath := strings.Replace(fromGroup, ".", "-", -1) + "-" + from.Version + "-" + fromLower
+	toPath := strings.Replace(toGroup, ".", "-", -1) + "-" + to.Version + "-" + toLower
+	words := map[string]string{
+		from.Resource:                  to.Resource,
+		fromGroup:                      toGroup,
+		fromGroup + "/" + from.Version: toGroup + "/" + to.Version,
+		"versions=" + from.Version:     "versions=" + to.Version,
+		fromPath:                       toPath,
+		// the names of the webhooks, e.g. mfrigate.kb.io
+		"m" + fromLower: "m" + toLower,
+		"v" + fromLower: "v" + toLower,
+	}
+	for _, suffix := range []string{"", "List", "Spec", "Status", "Reconciler"} {
+		words[from.Kind+suffix] = to.Kind + suffix
+	}
+	for _, suffix := range []string{"", "log"} {
+		words[fromLower+suffix] = toLower + suffix
+	}
+	r.words = internal.NewWords(words)
+
+	// the identifiers of the API package are renamed even if they are the
+	// same, for the package or the import name they are used with to change
+	r.api = internal.PackageRename{
+		Path:    path.Join(p.Repo, filepath.ToSlash(util.APIDir(from, p.MultiGroup))),
+		NewPath: path.Join(p.Repo, filepath.ToSlash(util.APIDir(to, p.MultiGroup))),
+		Name:    from.Group + from.Version,
+		NewName: to.Group + to.Version,
+		Idents:  map[string]string{},
+	}
+	for _, suffix := range []string{"", "List", "Spec", "Status"} {
+		r.api.Idents[from.Kind+suffix] = to.Kind + suffix
+	}
+	r.controllers = internal.PackageRename{
+		Path:    path.Join(p.Repo, filepath.ToSlash(util.ControllersDir(from.Group, p.MultiGroup))),
+		NewPath: path.Join(p.Repo, filepath.ToSlash(util.ControllersDir(to.Group, p.MultiGroup))),
+		Name:    "controllers",
+		NewName: "controllers",
+		Idents:  map[string]string{from.Kind + "Reconciler": to.Kind + "Reconciler"},
+	}
+	if p.MultiGroup {
+		// the controllers of every group are in a package named controllers
+		r.controllers.Name = from.Group + "controllers"
+		r.controllers.NewName = to.Group + "controllers"
+	}
+	return r
+}
+
+// GoFile renames the resource in the Go file at path and moves it to
+// newPath. The names in the comments and strings of the file are renamed if
+// the file is owned by the resource, or if it uses the renamed types. It
+// returns false if the file was not changed.
+func (r *Rename) GoFile(path, newPath string, owned bool) (bool, error) {
+	f, err := internal.LoadGoFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	rename := internal.GoRename{
+		Packages: []internal.PackageRename{r.api, r.controllers},
+		Words:    r.words,
+		Force:    owned,
+	}
+	switch filepath.Dir(path) {
+	case util.APIDir(r.from, r.multiGroup):
+		// the types stay in the package unless the version or group change,
+		// then only the files of the resource are moved with them
+		if owned || r.api.Path == r.api.NewPath {
+			rename.Idents = map[string]string{
+				strings.ToLower(r.from.Kind) + "log": strings.ToLower(r.to.Kind) + "log",
+			}
+			for ident, renamed := range r.api.Idents {
+				rename.Idents[ident] = renamed
+			}
+		}
+		if owned {
+			rename.Package = r.to.Version
+		}
+	case util.ControllersDir(r.from.Group, r.multiGroup):
+		rename.Idents = r.controllers.Idents
+	}
+
+	changed, err := f.Rename(rename)
+	if err != nil {
+		return false, err
+	}
+	if !changed && filepath.Clean(path) == filepath.Clean(newPath) {
+		return false, nil
+	}
+	return true, f.SaveAs(newPath)
+}
+
+// File renames the resource in the file at path, e.g. a manifest, and moves
+// it to newPath.
+func (r *Rename) File(path, newPath string) error {
+	return internal.RenameFile(path, newPath, r.words)
+}
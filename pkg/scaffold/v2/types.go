@@ -17,17 +17,15 @@ limitations under the License.
 package v2
 
 import (
-	"fmt"
-	"path/filepath"
-	"strings"
-
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
 )
 
 var _ input.File = &Types{}
 
-// Types scaffolds the api/<version>/<kind>_types.go file to define the schema for an API
+// Types scaffolds the api/<version>/<kind>_types.go file to define the schema for an API,
+// api/<group>/<version>/<kind>_types.go in multi group projects
 type Types struct {
 	input.Input
 
@@ -38,8 +36,7 @@ type Types struct {
 // GetInput implements input.File
 func (t *Types) GetInput() (input.Input, error) {
 	if t.Path == "" {
-		t.Path = filepath.Join("pkg", "apis", t.Resource.Group, t.Resource.Version,
-			fmt.Sprintf("%s_types.go", strings.ToLower(t.Resource.Kind)))
+		t.Path = util.TypesPath(t.Resource, t.MultiGroup)
 	}
 	t.TemplateBody = typesTemplate
 	t.IfExistsAction = input.Error
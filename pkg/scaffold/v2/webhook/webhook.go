@@ -65,21 +65,30 @@ func (a *Webhook) GetInput() (input.Input, error) {
 	}
 
 	if a.Path == "" {
-		a.Path = filepath.Join("api", a.Resource.Version,
+		a.Path = filepath.Join(util.APIDir(a.Resource, a.MultiGroup),
 			fmt.Sprintf("%s_webhook.go", strings.ToLower(a.Resource.Kind)))
 	}
-	if a.Defaulting {
-		WebhookTemplate = WebhookTemplate + DefaultingWebhookTemplate
-	}
-	if a.Validating {
-		WebhookTemplate = WebhookTemplate + ValidatingWebhookTemplate
+	var webhookTemplate string
+	for _, part := range a.TemplateParts() {
+		if part.Used {
+			webhookTemplate += part.Body
+		}
 	}
 
-	a.TemplateBody = WebhookTemplate
+	a.TemplateBody = webhookTemplate
 	a.Input.IfExistsAction = input.Error
 	return a.Input, nil
 }
 
+// TemplateParts implements input.TemplateParts
+func (a *Webhook) TemplateParts() []input.TemplatePart {
+	return []input.TemplatePart{
+		{Name: "base", Body: WebhookTemplate, Used: true},
+		{Name: "defaulting", Body: DefaultingWebhookTemplate, Used: a.Defaulting},
+		{Name: "validating", Body: ValidatingWebhookTemplate, Used: a.Validating},
+	}
+}
+
 var (
 	WebhookTemplate = `{{ .Boilerplate }}
 
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	resourcev1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/resource"
+	resourcev2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Webhook contains configuration for generating scaffolding for the webhooks
+// of an API resource.
+type Webhook struct {
+	Resource *resourcev1.Resource
+
+	project *input.ProjectFile
+
+	// Defaulting indicates whether to scaffold the defaulting webhook or not
+	Defaulting bool
+
+	// Validating indicates whether to scaffold the validating webhook or not
+	Validating bool
+
+	// Conversion indicates whether to scaffold the conversion webhook or not
+	Conversion bool
+
+	// EditPaths are the paths of the scaffolded files for the user to edit,
+	// set by Scaffold
+	EditPaths []string
+}
+
+// Validate validates whether Webhook scaffold has correct bits to generate
+// scaffolding for the webhooks.
+func (wh *Webhook) Validate() error {
+	if err := wh.setDefaults(); err != nil {
+		return err
+	}
+	if _, err := wh.layout(); err != nil {
+		return err
+	}
+	if !wh.Defaulting && !wh.Validating && !wh.Conversion {
+		return fmt.Errorf("kubebuilder webhook requires at least one of --defaulting, --programmatic-validation and --conversion to be true")
+	}
+	return wh.Resource.Validate()
+}
+
+func (wh *Webhook) setDefaults() error {
+	if wh.project == nil {
+		p, err := LoadProjectFile("PROJECT")
+		if err != nil {
+			return err
+		}
+		wh.project = &p
+	}
+	return nil
+}
+
+// Scaffold scaffolds the webhooks with the layout of the project.
+func (wh *Webhook) Scaffold() error {
+	if err := wh.setDefaults(); err != nil {
+		return err
+	}
+	l, err := wh.layout()
+	if err != nil {
+		return err
+	}
+	wh.EditPaths = nil
+	return l.ScaffoldWebhook(wh, wh.project)
+}
+
+// layout returns the layout of the project, which must support webhooks.
+func (wh *Webhook) layout() (*Layout, error) {
+	l, err := GetLayout(wh.project.Version)
+	if err != nil {
+		return nil, err
+	}
+	if l.ScaffoldWebhook == nil {
+		return nil, fmt.Errorf("kubebuilder create webhook is not supported for project version %s", wh.project.Version)
+	}
+	return l, nil
+}
+
+// ScaffoldV2 scaffolds the webhooks and wires them in main.go.
+func (wh *Webhook) ScaffoldV2(p *input.ProjectFile) error {
+	wh.EditPaths = append(wh.EditPaths, filepath.Join(util.APIDir(wh.Resource, p.MultiGroup),
+		fmt.Sprintf("%s_webhook.go", strings.ToLower(wh.Resource.Kind))))
+
+	err := (&Scaffold{}).Execute(
+		input.Options{},
+		&webhook.Webhook{
+			Resource:   wh.Resource,
+			Defaulting: wh.Defaulting,
+			Validating: wh.Validating,
+		},
+	)
+	if err != nil {
+		return withContext("error scaffolding webhook", err)
+	}
+
+	res := recordResource(p, wh.Resource)
+	if res.Webhooks == nil {
+		res.Webhooks = &input.ResourceWebhooks{}
+	}
+	res.Webhooks.Defaulting = res.Webhooks.Defaulting || wh.Defaulting
+	res.Webhooks.Validation = res.Webhooks.Validation || wh.Validating
+	res.Webhooks.Conversion = res.Webhooks.Conversion || wh.Conversion
+	err = saveProjectFile("PROJECT", p)
+	if err != nil {
+		return fmt.Errorf("error updating project file with resource information: %v", err)
+	}
+
+	err = (&resourcev2.Main{}).Update(
+		&resourcev2.MainUpdateOptions{
+			Project:        p,
+			WireResource:   false,
+			WireController: false,
+			WireWebhook:    true,
+			Resource:       wh.Resource,
+		})
+	if err != nil {
+		return withContext("error updating main.go", err)
+	}
+	return nil
+}
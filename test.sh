@@ -21,7 +21,7 @@ source common.sh
 
 function test_init_project {
   header_text "performing init project"
-  kubebuilder init --project-version 1 --domain example.com <<< "n"
+  kubebuilder init --project-version 1 --domain example.com --fetch-deps=false
 }
 
 function test_make_project {
@@ -31,58 +31,37 @@ function test_make_project {
 
 function test_create_api_controller {
   header_text "performing creating api and controller"
-  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced false <<EOF
-y
-y
-EOF
+  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced false --resource=true --controller=true
 }
 
 function test_create_namespaced_api_controller {
   header_text "performing creating namespaced api and controller"
-  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced true <<EOF
-y
-y
-EOF
+  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced true --resource=true --controller=true
 }
 
 function test_create_api_only {
   header_text "performing creating api only"
-  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced false <<EOF
-y
-n
-EOF
+  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced false --resource=true --controller=false
 }
 
 function test_create_namespaced_api_only {
   header_text "performing creating api only"
-  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced true <<EOF
-y
-n
-EOF
+  kubebuilder create api --group insect --version v1beta1 --kind Bee --namespaced true --resource=true --controller=false
 }
 
 function test_create_skip {
   header_text "performing creating but skipping everything"
-  kubebuilder create api --group insect --version v1beta1 --kind Bee <<EOF
-n
-n
-EOF
+  kubebuilder create api --group insect --version v1beta1 --kind Bee --resource=false --controller=false
 }
 
 function test_create_coretype_controller {
   header_text "performing creating coretype controller"
-  kubebuilder create api --group apps --version v1 --kind Deployment --namespaced false <<EOF
-n
-y
-EOF
+  kubebuilder create api --group apps --version v1 --kind Deployment --namespaced false --resource=false --controller=true
 }
 
 function test_create_namespaced_coretype_controller {
   header_text "performing creating coretype controller"
-  kubebuilder create api --group apps --version v1 --kind Deployment --namespaced true <<EOF
-n
-y
-EOF
+  kubebuilder create api --group apps --version v1 --kind Deployment --namespaced true --resource=false --controller=true
 }
 
 
@@ -158,4 +137,7 @@ GO111MODULE=auto test_project gopath/src/project 1
 # test project v2
 GO111MODULE=on test_project project-v2 2
 
+# test project v2 with the multigroup layout
+GO111MODULE=on test_project project-v2-multigroup 2
+
 exit $rc